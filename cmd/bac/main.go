@@ -1,9 +1,12 @@
 package main
 
 import (
+	"context"
 	goflag "flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/gabemontero/backstage-ai-cli/pkg/cmd/cli"
 	"github.com/gabemontero/backstage-ai-cli/pkg/util"
@@ -35,8 +38,12 @@ func main() {
 	}
 	initPFlags()
 
+	// cancel any in flight requests on an interrupt
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	rootCmd := cli.NewCmd()
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
+	if err != nil {
 		klog.Errorf("ERROR: %v\n", err)
 		os.Exit(1)
 	}
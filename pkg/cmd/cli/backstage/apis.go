@@ -1,9 +1,7 @@
 package backstage
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
+	"context"
 	"net/url"
 )
 
@@ -13,21 +11,19 @@ type listAPIs struct {
 	PageInfo   interface{}         `json:"pageInfo" yaml:"pageInfo"`
 }
 
-func (b *BackstageRESTClientWrapper) ListAPIs(qparms *url.Values) (string, error) {
+func (b *BackstageRESTClientWrapper) ListAPIs(ctx context.Context, qparms *url.Values) ([]ApiEntityV1alpha1, error) {
 	//TODO remove this post query filter logic if an exact query parameter check for the 'metadata.tags' array is determined
 	argsArr := b.pullSavedArgsFromQueryParams(qparms)
 
-	str, err := b.getWithKindParamFromBackstage(b.RootURL+QUERY_URI, qparms)
+	buf, err := b.getWithKindParamFromBackstage(ctx, b.RootURL+QUERY_URI, qparms)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	buf := []byte(str)
-
 	la := &listAPIs{}
-	err = json.Unmarshal(buf, la)
+	err = unmarshal(buf, la)
 	if err != nil {
-		return str, err
+		return nil, err
 	}
 
 	//TODO remove this post query filter logic if an exact query parameter check for the 'metadata.tags' array is determined
@@ -41,38 +37,32 @@ func (b *BackstageRESTClientWrapper) ListAPIs(qparms *url.Values) (string, error
 		la.Items = filteredAPIs
 	}
 
-	buf, err = json.MarshalIndent(la.Items, "", "    ")
-	return string(buf), err
+	return la.Items, nil
 }
 
-func (b *BackstageRESTClientWrapper) GetAPI(args ...string) (string, error) {
+func (b *BackstageRESTClientWrapper) GetAPI(ctx context.Context, args ...string) ([]ApiEntityV1alpha1, error) {
 	// example 'filter' value from swagger doc:  'kind=component,metadata.annotations.backstage.io/orphan=true'
-	filterValue := "kind=api,spec.type=openapi"
+	// with a filter for each of the kind's types, which Backstage ORs
+	filterValues := b.Types.Filters("api")
 	qparams := &url.Values{
-		"filter": []string{filterValue},
+		"filter": filterValues,
 	}
 	if len(args) == 0 {
-		return b.ListAPIs(qparams)
+		return b.ListAPIs(ctx, qparams)
 	}
 
 	if b.Tags {
-		qparams = updateQParams(b.Subset, filterValue, args, qparams)
-		return b.ListAPIs(qparams)
-	}
-
-	keys := buildKeys(args...)
-	buffer := &bytes.Buffer{}
-	for namespace, names := range keys {
-		for _, name := range names {
-			str, err := b.getFromBackstage(b.RootURL + fmt.Sprintf(API_URI, namespace, name))
-			if err != nil {
-				return buffer.String(), err
-			}
-			buf := []byte(str)
-			err = json.Indent(buffer, buf, "", "    ")
-			buffer.WriteString("\n")
-		}
+		qparams = updateQParams(b.Subset, filterValues, args, qparams)
+		return b.ListAPIs(ctx, qparams)
 	}
 
-	return buffer.String(), nil
+	// the entities found are returned even when some of the args are not found
+	apis := []ApiEntityV1alpha1{}
+	err := b.getEntitiesByRefs(ctx, "api", args, func(buf []byte) error {
+		api := ApiEntityV1alpha1{}
+		err := unmarshal(buf, &api)
+		apis = append(apis, api)
+		return err
+	})
+	return apis, err
 }
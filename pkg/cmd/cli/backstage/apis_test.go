@@ -1,6 +1,7 @@
 package backstage
 
 import (
+	"context"
 	"testing"
 )
 
@@ -9,10 +10,10 @@ func TestListAPIs(t *testing.T) {
 	defer ts.Close()
 
 	// Get with no args calls List
-	str, err := SetupBackstageTestRESTClient(ts).GetAPI()
+	items, err := SetupBackstageTestRESTClient(ts).GetAPI(context.Background())
 
 	AssertError(t, err)
-	AssertLineCompare(t, str, apis, 0)
+	AssertLineCompare(t, toJSON(t, items), apis, 0)
 }
 
 func TestGetAPIs(t *testing.T) {
@@ -20,21 +21,25 @@ func TestGetAPIs(t *testing.T) {
 	defer ts.Close()
 
 	nsName := "default:ollama-service-api"
-	str, err := SetupBackstageTestRESTClient(ts).GetAPI(nsName)
-
+	items, err := SetupBackstageTestRESTClient(ts).GetAPI(context.Background(), nsName)
 	AssertError(t, err)
-	AssertContains(t, str, nsName)
+	AssertEqual(t, 1, len(items))
+	AssertEqual(t, nsName, items[0].Metadata.Namespace+":"+items[0].Metadata.Name)
 }
 
 func TestGetAPIsError(t *testing.T) {
 	ts := CreateServer(t)
 	defer ts.Close()
 
-	nsName := "404:404"
-	_, err := SetupBackstageTestRESTClient(ts).GetAPI(nsName)
-	if err == nil {
-		t.Error("expected error")
+	items, err := SetupBackstageTestRESTClient(ts).GetAPI(context.Background(), "404:404", "default:found", "404:also-missing")
+	notFound, ok := err.(*NotFoundError)
+	if !ok {
+		t.Fatalf("expected a not found error but got %v", err)
 	}
+	AssertEqual(t, []string{"api:404/404", "api:404/also-missing"}, notFound.Refs)
+	AssertContains(t, err.Error(), "api:404/404: not found")
+	AssertEqual(t, 1, len(items))
+	AssertEqual(t, "found", items[0].Metadata.Name)
 }
 
 func TestGetAPIsWithTags(t *testing.T) {
@@ -63,9 +68,9 @@ func TestGetAPIsWithTags(t *testing.T) {
 		},
 	} {
 		bs.Subset = tc.subset
-		str, err := bs.GetAPI(tc.args...)
+		items, err := bs.GetAPI(context.Background(), tc.args...)
 		AssertError(t, err)
-		AssertLineCompare(t, str, tc.str, 0)
+		AssertLineCompare(t, toJSON(t, items), tc.str, 0)
 	}
 }
 
@@ -1,9 +1,7 @@
 package backstage
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
+	"context"
 	"net/url"
 )
 
@@ -13,21 +11,19 @@ type listComponents struct {
 	PageInfo   interface{}               `json:"pageInfo" yaml:"pageInfo"`
 }
 
-func (b *BackstageRESTClientWrapper) ListComponents(qparms *url.Values) (string, error) {
+func (b *BackstageRESTClientWrapper) ListComponents(ctx context.Context, qparms *url.Values) ([]ComponentEntityV1alpha1, error) {
 	//TODO remove this post query filter logic if an exact query parameter check for the 'metadata.tags' array is determined
 	argsArr := b.pullSavedArgsFromQueryParams(qparms)
 
-	str, err := b.getWithKindParamFromBackstage(b.RootURL+QUERY_URI, qparms)
+	buf, err := b.getWithKindParamFromBackstage(ctx, b.RootURL+QUERY_URI, qparms)
 	if err != nil {
-		return str, err
+		return nil, err
 	}
 
-	buf := []byte(str)
-
 	lc := &listComponents{}
-	err = json.Unmarshal(buf, lc)
+	err = unmarshal(buf, lc)
 	if err != nil {
-		return str, err
+		return nil, err
 	}
 
 	//TODO remove this post query filter logic if an exact query parameter check for the 'metadata.tags' array is determined
@@ -41,38 +37,32 @@ func (b *BackstageRESTClientWrapper) ListComponents(qparms *url.Values) (string,
 		lc.Items = filteredComponents
 	}
 
-	buf, err = json.MarshalIndent(lc.Items, "", "    ")
-	return string(buf), err
+	return lc.Items, nil
 }
 
-func (b *BackstageRESTClientWrapper) GetComponent(args ...string) (string, error) {
+func (b *BackstageRESTClientWrapper) GetComponent(ctx context.Context, args ...string) ([]ComponentEntityV1alpha1, error) {
 	// example 'filter' value from swagger doc:  'kind=component,metadata.annotations.backstage.io/orphan=true'
-	filterValue := "kind=component,spec.type=model-server"
+	// with a filter for each of the kind's types, which Backstage ORs
+	filterValues := b.Types.Filters("component")
 	qparams := &url.Values{
-		"filter": []string{filterValue},
+		"filter": filterValues,
 	}
 	if len(args) == 0 {
-		return b.ListComponents(qparams)
+		return b.ListComponents(ctx, qparams)
 	}
 
 	if b.Tags {
-		qparams = updateQParams(b.Subset, filterValue, args, qparams)
-		return b.ListComponents(qparams)
-	}
-
-	keys := buildKeys(args...)
-	buffer := &bytes.Buffer{}
-	for namespace, names := range keys {
-		for _, name := range names {
-			str, err := b.getFromBackstage(b.RootURL + fmt.Sprintf(COMPONENT_URI, namespace, name))
-			if err != nil {
-				return buffer.String(), err
-			}
-			buf := []byte(str)
-			err = json.Indent(buffer, buf, "", "    ")
-			buffer.WriteString("\n")
-		}
+		qparams = updateQParams(b.Subset, filterValues, args, qparams)
+		return b.ListComponents(ctx, qparams)
 	}
 
-	return buffer.String(), nil
+	// the entities found are returned even when some of the args are not found
+	components := []ComponentEntityV1alpha1{}
+	err := b.getEntitiesByRefs(ctx, "component", args, func(buf []byte) error {
+		component := ComponentEntityV1alpha1{}
+		err := unmarshal(buf, &component)
+		components = append(components, component)
+		return err
+	})
+	return components, err
 }
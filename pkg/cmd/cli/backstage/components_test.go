@@ -1,6 +1,7 @@
 package backstage
 
 import (
+	"context"
 	"testing"
 )
 
@@ -9,9 +10,9 @@ func TestListComponents(t *testing.T) {
 	defer ts.Close()
 
 	// Get with no args calls List
-	str, err := SetupBackstageTestRESTClient(ts).GetComponent()
+	items, err := SetupBackstageTestRESTClient(ts).GetComponent(context.Background())
 	AssertError(t, err)
-	AssertLineCompare(t, str, components, 0)
+	AssertLineCompare(t, toJSON(t, items), components, 0)
 }
 
 func TestGetComponents(t *testing.T) {
@@ -19,21 +20,25 @@ func TestGetComponents(t *testing.T) {
 	defer ts.Close()
 
 	nsName := "default:ollama-service-component"
-	str, err := SetupBackstageTestRESTClient(ts).GetComponent(nsName)
-
+	items, err := SetupBackstageTestRESTClient(ts).GetComponent(context.Background(), nsName)
 	AssertError(t, err)
-	AssertContains(t, str, nsName)
+	AssertEqual(t, 1, len(items))
+	AssertEqual(t, nsName, items[0].Metadata.Namespace+":"+items[0].Metadata.Name)
 }
 
 func TestGetComponentsError(t *testing.T) {
 	ts := CreateServer(t)
 	defer ts.Close()
 
-	nsName := "404:404"
-	_, err := SetupBackstageTestRESTClient(ts).GetComponent(nsName)
-	if err == nil {
-		t.Error("expected error")
+	items, err := SetupBackstageTestRESTClient(ts).GetComponent(context.Background(), "404:404", "default:found", "404:also-missing")
+	notFound, ok := err.(*NotFoundError)
+	if !ok {
+		t.Fatalf("expected a not found error but got %v", err)
 	}
+	AssertEqual(t, []string{"component:404/404", "component:404/also-missing"}, notFound.Refs)
+	AssertContains(t, err.Error(), "component:404/404: not found")
+	AssertEqual(t, 1, len(items))
+	AssertEqual(t, "found", items[0].Metadata.Name)
 }
 
 func TestGetComponentsWithTags(t *testing.T) {
@@ -62,9 +67,9 @@ func TestGetComponentsWithTags(t *testing.T) {
 		},
 	} {
 		bs.Subset = tc.subset
-		str, err := bs.GetComponent(tc.args...)
+		items, err := bs.GetComponent(context.Background(), tc.args...)
 		AssertError(t, err)
-		AssertLineCompare(t, str, tc.str, 0)
+		AssertLineCompare(t, toJSON(t, items), tc.str, 0)
 	}
 }
 
@@ -1,12 +1,57 @@
 package backstage
 
 const (
-	COMPONENT_TYPE     = "model-server"
-	RESOURCE_TYPE      = "api-model"
-	API_TYPE           = "openapi"
-	LINK_API_URL       = "API URL"
-	LINK_TYPE_WEBSITE  = "website"
-	LINK_ICON_WEBASSET = "WebAsset"
-	TECHDOC_REFS       = "backstage.io/techdocs-ref"
-	VERSION            = "backstage.io/v1alpha1"
+	COMPONENT_TYPE              = "model-server"
+	RESOURCE_TYPE               = "api-model"
+	API_TYPE                    = "openapi"
+	SYSTEM_TYPE                 = "model-serving-environment"
+	LINK_API_URL                = "API URL"
+	LINK_MODEL_SERVING_URL      = "model serving URL"
+	LINK_REST_MODEL_SERVING_URL = "REST model serving URL"
+	LINK_GRPC_MODEL_SERVING_URL = "GRPC model serving URL"
+	LINK_FASTAPI_URL            = "FastAPI URL"
+	LINK_TYPE_WEBSITE           = "website"
+	LINK_ICON_WEBASSET          = "WebAsset"
+	TECHDOC_REFS                = "backstage.io/techdocs-ref"
+	TECHDOC_REFS_DIR_PREFIX     = "dir:"
+	VERSION                     = "backstage.io/v1alpha1"
 )
+
+// The annotations describing where a model comes from and where it is served.  The Kubernetes ones are those the
+// Backstage Kubernetes plugin uses to find the pods of an entity.
+const (
+	MODEL_FORMAT_ANNOTATION              = "ai.backstage.io/model-format"
+	MODEL_FORMAT_VERSION_ANNOTATION      = "ai.backstage.io/model-format-version"
+	SERVING_RUNTIME_ANNOTATION           = "ai.backstage.io/serving-runtime"
+	STORAGE_URI_ANNOTATION               = "ai.backstage.io/storage-uri"
+	DEPLOYMENT_MODE_ANNOTATION           = "ai.backstage.io/deployment-mode"
+	REGISTERED_MODEL_ID_ANNOTATION       = "ai.backstage.io/registered-model-id"
+	MODEL_VERSION_ID_ANNOTATION          = "ai.backstage.io/model-version-id"
+	MODEL_ARTIFACT_ID_ANNOTATION         = "ai.backstage.io/model-artifact-id"
+	KUBERNETES_NAME_ANNOTATION           = "ai.backstage.io/kubernetes-name"
+	SOURCE_ANNOTATION                    = "ai.backstage.io/source"
+	KUBERNETES_ID_ANNOTATION             = "backstage.io/kubernetes-id"
+	KUBERNETES_NAMESPACE_ANNOTATION      = "backstage.io/kubernetes-namespace"
+	KUBERNETES_LABEL_SELECTOR_ANNOTATION = "backstage.io/kubernetes-label-selector"
+	KSERVE_INFERENCE_SERVICE_LABEL       = "serving.kserve.io/inferenceservice"
+	SOURCE_KSERVE                        = "kserve"
+	SOURCE_KUBEFLOW                      = "kubeflow-model-registry"
+)
+
+// The annotations reporting whether a model is being served, from the status of the InferenceService
+const (
+	READY_ANNOTATION                   = "ai.backstage.io/ready"
+	PREDICTOR_READY_ANNOTATION         = "ai.backstage.io/predictor-ready"
+	INGRESS_READY_ANNOTATION           = "ai.backstage.io/ingress-ready"
+	MODEL_STATE_ANNOTATION             = "ai.backstage.io/model-state"
+	MODEL_TARGET_STATE_ANNOTATION      = "ai.backstage.io/model-target-state"
+	MODEL_TRANSITION_STATUS_ANNOTATION = "ai.backstage.io/model-transition-status"
+	MODEL_LAST_FAILURE_ANNOTATION      = "ai.backstage.io/model-last-failure"
+	TRAFFIC_ANNOTATION                 = "ai.backstage.io/traffic"
+	MODEL_REVISION_ANNOTATION          = "ai.backstage.io/model-revision"
+	MODEL_REVISION_ROLE_ANNOTATION     = "ai.backstage.io/model-revision-role"
+	TRAFFIC_PERCENT_ANNOTATION         = "ai.backstage.io/traffic-percent"
+)
+
+// API_DEFINITION_PLACEHOLDER is used when no definition is available, as Backstage rejects an API with an empty definition
+const API_DEFINITION_PLACEHOLDER = "no-definition-yet"
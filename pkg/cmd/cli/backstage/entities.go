@@ -1,18 +1,292 @@
 package backstage
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
 )
 
-func (b *BackstageRESTClientWrapper) ListEntities() (string, error) {
-	str, err := b.getFromBackstage(b.RootURL + ENTITIES_URI)
+func (b *BackstageRESTClientWrapper) ListEntities(ctx context.Context) ([]Entity, error) {
+	buf, err := b.getFromBackstage(ctx, b.RootURL+ENTITIES_URI)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	buf := []byte(str)
-	buffer := &bytes.Buffer{}
-	err = json.Indent(buffer, buf, "", "    ")
-	return buffer.String(), err
+	entities := []Entity{}
+	err = unmarshal(buf, &entities)
+	return entities, err
+}
+
+// ParseEntityRef splits a 'kind:namespace/name' entity reference, where the namespace is optional, into its parts
+func ParseEntityRef(ref string) (kind, namespace, name string, err error) {
+	kind, rest, found := strings.Cut(ref, ":")
+	if !found || len(kind) == 0 || len(rest) == 0 {
+		return "", "", "", fmt.Errorf("entity reference %q is not of the form kind:namespace/name", ref)
+	}
+	namespace, name, found = strings.Cut(rest, "/")
+	if !found {
+		namespace, name = DEFAULT_NS, rest
+	}
+	if len(namespace) == 0 || len(name) == 0 {
+		return "", "", "", fmt.Errorf("entity reference %q is not of the form kind:namespace/name", ref)
+	}
+	return strings.ToLower(kind), namespace, name, nil
+}
+
+// GetEntity fetches the entity for a 'kind:namespace/name' reference
+func (b *BackstageRESTClientWrapper) GetEntity(ctx context.Context, ref string) (*Entity, error) {
+	kind, namespace, name, err := ParseEntityRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	entityURL := b.RootURL + fmt.Sprintf(ENTITY_URI, kind, namespace, name)
+	resp, err := b.RESTClient.R().SetContext(ctx).SetAuthToken(b.Token).SetHeader("Accept", "application/json").Get(entityURL)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode() == 404 {
+		return nil, &NotFoundError{Refs: []string{ref}}
+	}
+	buf, err := b.processFetch(resp, entityURL, "get")
+	if err != nil {
+		return nil, err
+	}
+	entity := &Entity{}
+	err = unmarshal(buf, entity)
+	if err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+// DeleteEntity removes the entity for a 'kind:namespace/name' reference, returning the entity as it was before the
+// delete.  Backstage only deletes entities by their UID, so the entity is fetched first.
+func (b *BackstageRESTClientWrapper) DeleteEntity(ctx context.Context, ref string) (*Entity, error) {
+	entity, err := b.GetEntity(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if len(entity.Metadata.UID) == 0 {
+		return nil, fmt.Errorf("Backstage returned entity %s without a uid", ref)
+	}
+	return entity, b.DeleteEntityByUID(ctx, entity.Metadata.UID)
+}
+
+func (b *BackstageRESTClientWrapper) DeleteEntityByUID(ctx context.Context, uid string) error {
+	return b.deleteFromBackstage(ctx, b.RootURL+fmt.Sprintf(ENTITY_UID_URI, uid))
+}
+
+// EntityAncestry is the chain of entities, typically locations, that led the catalog to the root entity
+type EntityAncestry struct {
+	RootEntityRef string               `json:"rootEntityRef" yaml:"rootEntityRef"`
+	Items         []EntityAncestryItem `json:"items" yaml:"items"`
+}
+
+type EntityAncestryItem struct {
+	Entity           Entity   `json:"entity" yaml:"entity"`
+	ParentEntityRefs []string `json:"parentEntityRefs" yaml:"parentEntityRefs"`
+}
+
+// GetEntityAncestry fetches the ancestry of the entity for a 'kind:namespace/name' reference
+func (b *BackstageRESTClientWrapper) GetEntityAncestry(ctx context.Context, ref string) (*EntityAncestry, error) {
+	kind, namespace, name, err := ParseEntityRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	buf, err := b.getFromBackstage(ctx, b.RootURL+fmt.Sprintf(ANCESTRY_URI, kind, namespace, name))
+	if err != nil {
+		return nil, err
+	}
+	ancestry := &EntityAncestry{}
+	err = unmarshal(buf, ancestry)
+	if err != nil {
+		return nil, err
+	}
+	return ancestry, nil
+}
+
+// NotFoundError lists the entity references the catalog has no entity for, where other references in the same request
+// may have been found.  GetEntity also returns it, for its single reference.
+type NotFoundError struct {
+	Refs []string
+}
+
+func (e *NotFoundError) Error() string {
+	lines := []string{}
+	for _, ref := range e.Refs {
+		lines = append(lines, fmt.Sprintf("%s: not found", ref))
+	}
+	return strings.Join(lines, "\n")
+}
+
+type byRefsResponse struct {
+	Items []json.RawMessage `json:"items"`
+}
+
+// getEntitiesByRefs fetches the entities for the 'namespace:name' args of the kind in a single request, decoding each
+// one found with the provided function.  The references without an entity are returned in a NotFoundError.
+func (b *BackstageRESTClientWrapper) getEntitiesByRefs(ctx context.Context, kind string, args []string, decode func([]byte) error) error {
+	refs := []string{}
+	for _, key := range buildKeys(args...) {
+		refs = append(refs, fmt.Sprintf("%s:%s/%s", kind, key.namespace, key.name))
+	}
+	buf, err := b.postToBackstage(ctx, b.RootURL+BY_REFS_URI, map[string]interface{}{"entityRefs": refs})
+	if err != nil {
+		return err
+	}
+	resp := &byRefsResponse{}
+	err = unmarshal(buf, resp)
+	if err != nil {
+		return err
+	}
+	if len(resp.Items) != len(refs) {
+		return fmt.Errorf("%s returned %d items for %d entity refs", BY_REFS_URI, len(resp.Items), len(refs))
+	}
+	notFound := &NotFoundError{}
+	for i, item := range resp.Items {
+		// the items line up with the requested refs, with null for those that do not exist
+		if len(item) == 0 || string(item) == "null" {
+			notFound.Refs = append(notFound.Refs, refs[i])
+			continue
+		}
+		if err = decode(item); err != nil {
+			return err
+		}
+	}
+	if len(notFound.Refs) > 0 {
+		return notFound
+	}
+	return nil
+}
+
+type listEntities struct {
+	Items    []Entity `json:"items"`
+	PageInfo struct {
+		NextCursor string `json:"nextCursor"`
+	} `json:"pageInfo"`
+}
+
+// QueryEntities returns every entity matching any of the filters, following the cursor Backstage pages the results with
+func (b *BackstageRESTClientWrapper) QueryEntities(ctx context.Context, filters ...string) ([]Entity, error) {
+	entities := []Entity{}
+	qparams := &url.Values{FILTER_PARAM: filters}
+	for {
+		buf, err := b.getWithKindParamFromBackstage(ctx, b.RootURL+QUERY_URI, qparams)
+		if err != nil {
+			return entities, err
+		}
+		list := &listEntities{}
+		err = unmarshal(buf, list)
+		if err != nil {
+			return entities, err
+		}
+		entities = append(entities, list.Items...)
+		if len(list.PageInfo.NextCursor) == 0 {
+			return entities, nil
+		}
+		// the cursor carries the filter, so Backstage does not allow both on the same request
+		qparams = &url.Values{CURSOR_PARAM: []string{list.PageInfo.NextCursor}}
+	}
+}
+
+// FacetCount is the number of entities with a value for a facet, such as a 'spec.type'
+type FacetCount struct {
+	Value string `json:"value" yaml:"value"`
+	Count int    `json:"count" yaml:"count"`
+}
+
+type facetsResponse struct {
+	Facets map[string][]FacetCount `json:"facets"`
+}
+
+// GetEntityFacets counts the values of each facet, for instance 'spec.owner', across the entities matching any of
+// the filters
+func (b *BackstageRESTClientWrapper) GetEntityFacets(ctx context.Context, facets []string, filters ...string) (map[string][]FacetCount, error) {
+	qparams := &url.Values{FILTER_PARAM: filters, FACET_PARAM: facets}
+	buf, err := b.getWithKindParamFromBackstage(ctx, b.RootURL+FACETS_URI, qparams)
+	if err != nil {
+		return nil, err
+	}
+	resp := &facetsResponse{}
+	err = unmarshal(buf, resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Facets, nil
+}
+
+// GetLocationEntities returns the entities Backstage read from the location
+func (b *BackstageRESTClientWrapper) GetLocationEntities(ctx context.Context, location Location) ([]Entity, error) {
+	return b.QueryEntities(ctx, fmt.Sprintf("metadata.annotations.%s=%s:%s", MANAGED_BY_LOCATION_ANNOTATION, location.Type, location.Target))
+}
+
+type validateEntityResponse struct {
+	Errors []SerializedError `json:"errors"`
+}
+
+// ValidateEntityRemote has the Backstage catalog processors validate the entity without storing it. The location
+// is a location reference, like 'url:https://github.com/my-org/my-repo/blob/main/catalog-info.yaml', that the entity
+// would be imported from.  The returned slice holds the processor errors for an invalid entity.
+func (b *BackstageRESTClientWrapper) ValidateEntityRemote(ctx context.Context, entity map[string]interface{}, location string) ([]error, error) {
+	rc, buf, err := b.postForResult(ctx, b.RootURL+VALIDATE_URI, map[string]interface{}{"entity": entity, "location": location})
+	if err != nil {
+		return nil, err
+	}
+	if rc != 400 {
+		return nil, nil
+	}
+	resp := &validateEntityResponse{}
+	err = unmarshal(buf, resp)
+	if err != nil {
+		return nil, err
+	}
+	errs := []error{}
+	for _, e := range resp.Errors {
+		errs = append(errs, fmt.Errorf("%s: %s", e.Name, e.Message))
+	}
+	if len(errs) == 0 {
+		// a 400 without any detail is still a rejection of the entity
+		errs = append(errs, fmt.Errorf("rejected by %s: %s", VALIDATE_URI, string(buf)))
+	}
+	return errs, nil
+}
+
+// catalogAnnotations are added by the catalog when it reads an entity from a location, rather than written in its YAML
+var catalogAnnotations = []string{
+	MANAGED_BY_LOCATION_ANNOTATION,
+	MANAGED_BY_ORIGIN_ANNOTATION,
+	ORPHAN_ANNOTATION,
+	VIEW_URL_ANNOTATION,
+	EDIT_URL_ANNOTATION,
+	SOURCE_LOCATION_ANNOTATION,
+}
+
+// SourceEntity is the entity as it would be written in YAML, without the uid, etag, relations, status, and
+// annotations the catalog adds when processing it
+func SourceEntity(entity Entity) (map[string]interface{}, error) {
+	entity.Metadata.UID = ""
+	entity.Metadata.Etag = ""
+	entity.Relations = nil
+	entity.Status = nil
+	annotations := map[string]string{}
+	for key, value := range entity.Metadata.Annotations {
+		annotations[key] = value
+	}
+	for _, key := range catalogAnnotations {
+		delete(annotations, key)
+	}
+	entity.Metadata.Annotations = annotations
+	if len(annotations) == 0 {
+		entity.Metadata.Annotations = nil
+	}
+
+	buf, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	source := map[string]interface{}{}
+	err = json.Unmarshal(buf, &source)
+	return source, err
 }
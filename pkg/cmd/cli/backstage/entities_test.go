@@ -1,6 +1,10 @@
 package backstage
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"testing"
 )
 
@@ -8,7 +12,127 @@ func TestListEntities(t *testing.T) {
 	ts := CreateServer(t)
 	defer ts.Close()
 
-	str, err := SetupBackstageTestRESTClient(ts).ListEntities()
+	entities, err := SetupBackstageTestRESTClient(ts).ListEntities(context.Background())
 	AssertError(t, err)
-	AssertEqual(t, TestJSONStringIndented, str)
+	AssertEqual(t, 2, len(entities))
+	AssertEqual(t, "Component:default/my-component", EntityRef(entities[0]))
+	AssertEqual(t, "API:ai/my-api", EntityRef(entities[1]))
+	AssertEqual(t, "resource:default/my-model-v1", Ref("resource", "", "my-model-v1"))
+	AssertEqual(t, "user:team-a/me", Ref("user", "team-a", "me"))
+}
+
+func TestValidateEntityRemote(t *testing.T) {
+	ts := CreateServer(t)
+	defer ts.Close()
+
+	entity := map[string]interface{}{"apiVersion": VERSION, "kind": KindAPI, "metadata": map[string]interface{}{"name": "valid"}}
+	errs, err := SetupBackstageTestRESTClient(ts).ValidateEntityRemote(context.Background(), entity, "url:https://my-repo/my.yaml")
+	AssertError(t, err)
+	AssertEqual(t, 0, len(errs))
+
+	entity["metadata"] = map[string]interface{}{"name": "invalid"}
+	errs, err = SetupBackstageTestRESTClient(ts).ValidateEntityRemote(context.Background(), entity, "url:https://my-repo/my.yaml")
+	AssertError(t, err)
+	AssertEqual(t, 1, len(errs))
+	AssertContains(t, errs[0].Error(), "must have required property 'definition'")
+}
+
+func TestDeleteEntity(t *testing.T) {
+	ts := CreateServer(t)
+	defer ts.Close()
+
+	entity, err := SetupBackstageTestRESTClient(ts).DeleteEntity(context.Background(), "component:default/my-model")
+	AssertError(t, err)
+	AssertEqual(t, "uid-my-model", entity.Metadata.UID)
+
+	_, err = SetupBackstageTestRESTClient(ts).DeleteEntity(context.Background(), "component:404/my-model")
+	if err == nil {
+		t.Error("expected error")
+	}
+	err = SetupBackstageTestRESTClient(ts).DeleteEntityByUID(context.Background(), "uid-404")
+	if err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestGetEntityAncestry(t *testing.T) {
+	ts := CreateServer(t)
+	defer ts.Close()
+
+	ancestry, err := SetupBackstageTestRESTClient(ts).GetEntityAncestry(context.Background(), "component:default/my-model")
+	AssertError(t, err)
+	AssertEqual(t, "component:default/my-model", ancestry.RootEntityRef)
+	AssertEqual(t, 2, len(ancestry.Items))
+	AssertEqual(t, []string{"location:default/generated-abc"}, ancestry.Items[0].ParentEntityRefs)
+	AssertEqual(t, "https://my-repo/my.yaml", ancestry.Items[1].Entity.Spec["target"])
+}
+
+func TestGetEntityFacets(t *testing.T) {
+	ts := CreateTestServer(func(w http.ResponseWriter, r *http.Request) {
+		AssertEqual(t, FACETS_URI, r.URL.Path)
+		AssertEqual(t, []string{"spec.type", "spec.owner"}, r.URL.Query()[FACET_PARAM])
+		AssertEqual(t, []string{"kind=component", "kind=api"}, r.URL.Query()[FILTER_PARAM])
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"facets":{"spec.type":[{"value":"model-server","count":2},{"value":"openapi","count":1}],"spec.owner":[{"value":"team-a","count":3}]}}`))
+	})
+	defer ts.Close()
+
+	facets, err := SetupBackstageTestRESTClient(ts).GetEntityFacets(context.Background(), []string{"spec.type", "spec.owner"}, "kind=component", "kind=api")
+	AssertError(t, err)
+	AssertEqual(t, []FacetCount{{Value: "model-server", Count: 2}, {Value: "openapi", Count: 1}}, facets["spec.type"])
+	AssertEqual(t, []FacetCount{{Value: "team-a", Count: 3}}, facets["spec.owner"])
+}
+
+func TestQueryEntitiesFilters(t *testing.T) {
+	ts := CreateTestServer(func(w http.ResponseWriter, r *http.Request) {
+		AssertEqual(t, []string{"kind=component", "kind=api"}, r.URL.Query()[FILTER_PARAM])
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"items":[%s],"pageInfo":{}}`, fmt.Sprintf(TestEntityJSON, KindComponent, "my-model", "default"))))
+	})
+	defer ts.Close()
+
+	entities, err := SetupBackstageTestRESTClient(ts).QueryEntities(context.Background(), "kind=component", "kind=api")
+	AssertError(t, err)
+	AssertEqual(t, 1, len(entities))
+}
+
+func TestGetEntityNotFound(t *testing.T) {
+	ts := CreateServer(t)
+	defer ts.Close()
+
+	_, err := SetupBackstageTestRESTClient(ts).GetEntity(context.Background(), "component:404/my-model")
+	notFound := &NotFoundError{}
+	if !errors.As(err, &notFound) {
+		t.Fatalf("expected a NotFoundError, got %v", err)
+	}
+	AssertEqual(t, []string{"component:404/my-model"}, notFound.Refs)
+}
+
+func TestSourceEntity(t *testing.T) {
+	entity := Entity{
+		ApiVersion: VERSION,
+		Kind:       KindAPI,
+		Metadata: EntityMeta{
+			UID:  "uid-my-model",
+			Etag: "etag",
+			Name: "my-model",
+			Annotations: map[string]string{
+				MANAGED_BY_LOCATION_ANNOTATION: "url:https://my-repo/catalog-info.yaml",
+				MANAGED_BY_ORIGIN_ANNOTATION:   "url:https://my-repo/catalog-info.yaml",
+				TECHDOC_REFS:                   "dir:.",
+			},
+		},
+		Spec:      map[string]interface{}{"type": API_TYPE},
+		Relations: []EntityRelation{{Type: "ownedBy", TargetRef: "user:default/exampleuser"}},
+		Status:    &EntityStatus{},
+	}
+	source, err := SourceEntity(entity)
+	AssertError(t, err)
+	AssertEqual(t, map[string]interface{}{
+		"apiVersion": VERSION,
+		"kind":       KindAPI,
+		"metadata":   map[string]interface{}{"name": "my-model", "annotations": map[string]interface{}{TECHDOC_REFS: "dir:."}},
+		"spec":       map[string]interface{}{"type": API_TYPE},
+	}, source)
+	AssertEqual(t, 3, len(entity.Metadata.Annotations))
 }
@@ -0,0 +1,111 @@
+package backstage
+
+import (
+	"fmt"
+	"github.com/gabemontero/backstage-ai-cli/pkg/config"
+	"strings"
+)
+
+// EntityTypes is the spec.type vocabulary of the AI related entities of each kind.  The first type of a kind is the
+// one new-model generates, and an entity with any of the types counts as AI related when querying the catalog.
+type EntityTypes struct {
+	Component []string
+	Resource  []string
+	API       []string
+	System    []string
+}
+
+// DefaultEntityTypes are the types used for a kind when none are configured.  Resources have long been queried as
+// 'ai-model' while new-model generates 'api-model', so both count.
+func DefaultEntityTypes() EntityTypes {
+	return EntityTypes{
+		Component: []string{COMPONENT_TYPE},
+		Resource:  []string{RESOURCE_TYPE, "ai-model"},
+		API:       []string{API_TYPE},
+		System:    []string{SYSTEM_TYPE},
+	}
+}
+
+// NewEntityTypes is the configured vocabulary, with the defaults for the kinds that have no types configured
+func NewEntityTypes(cfg *config.Config) EntityTypes {
+	defaults := DefaultEntityTypes()
+	orDefault := func(configured, def []string) []string {
+		types := []string{}
+		for _, t := range configured {
+			if t = strings.TrimSpace(t); len(t) > 0 {
+				types = append(types, t)
+			}
+		}
+		if len(types) == 0 {
+			return def
+		}
+		return types
+	}
+	return EntityTypes{
+		Component: orDefault(cfg.ComponentTypes, defaults.Component),
+		Resource:  orDefault(cfg.ResourceTypes, defaults.Resource),
+		API:       orDefault(cfg.APITypes, defaults.API),
+		System:    orDefault(cfg.SystemTypes, defaults.System),
+	}
+}
+
+// ForKind is the types of the kind, with the defaults when none are set
+func (t EntityTypes) ForKind(kind string) []string {
+	defaults := DefaultEntityTypes()
+	var types, def []string
+	switch strings.ToLower(kind) {
+	case "component":
+		types, def = t.Component, defaults.Component
+	case "resource":
+		types, def = t.Resource, defaults.Resource
+	case "api":
+		types, def = t.API, defaults.API
+	case "system":
+		types, def = t.System, defaults.System
+	}
+	if len(types) == 0 {
+		return def
+	}
+	return types
+}
+
+// Filters are the 'filter' query parameters for the entities of the kind with any of its types, as Backstage ORs
+// the filters
+func (t EntityTypes) Filters(kind string) []string {
+	filters := []string{}
+	for _, specType := range t.ForKind(kind) {
+		filters = append(filters, fmt.Sprintf("kind=%s,spec.type=%s", strings.ToLower(kind), specType))
+	}
+	return filters
+}
+
+// ModelFilters are the filters for the Components, Resources, and APIs that describe models
+func (t EntityTypes) ModelFilters() []string {
+	filters := t.Filters("component")
+	filters = append(filters, t.Filters("resource")...)
+	return append(filters, t.Filters("api")...)
+}
+
+// All is every type of every kind, Systems included
+func (t EntityTypes) All() []string {
+	all := []string{}
+	for _, kind := range []string{"component", "resource", "api", "system"} {
+		all = append(all, t.ForKind(kind)...)
+	}
+	return all
+}
+
+// EntityTypesPopulator is implemented by populators that generate entities with the configured types rather than the
+// defaults
+type EntityTypesPopulator interface {
+	GetEntityTypes() EntityTypes
+}
+
+// getType is the spec.type new-model generates for an entity of the kind
+func getType(pop CommonPopulator, kind string) string {
+	types := DefaultEntityTypes()
+	if typed, ok := pop.(EntityTypesPopulator); ok {
+		types = typed.GetEntityTypes()
+	}
+	return types.ForKind(kind)[0]
+}
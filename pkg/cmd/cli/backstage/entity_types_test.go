@@ -0,0 +1,75 @@
+package backstage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/gabemontero/backstage-ai-cli/pkg/config"
+	"net/http"
+	"testing"
+)
+
+func TestNewEntityTypes(t *testing.T) {
+	types := NewEntityTypes(&config.Config{ComponentTypes: []string{"llm", " embedding-model "}, APITypes: []string{""}})
+	AssertEqual(t, []string{"llm", "embedding-model"}, types.ForKind("Component"))
+	AssertEqual(t, []string{RESOURCE_TYPE, "ai-model"}, types.ForKind("resource"))
+	AssertEqual(t, []string{API_TYPE}, types.ForKind("api"))
+	AssertEqual(t, []string{"kind=component,spec.type=llm", "kind=component,spec.type=embedding-model"}, types.Filters("Component"))
+	AssertEqual(t, []string{
+		"kind=component,spec.type=llm",
+		"kind=component,spec.type=embedding-model",
+		"kind=resource,spec.type=api-model",
+		"kind=resource,spec.type=ai-model",
+		"kind=api,spec.type=openapi",
+	}, types.ModelFilters())
+	AssertEqual(t, []string{"llm", "embedding-model", "api-model", "ai-model", "openapi", SYSTEM_TYPE}, types.All())
+
+	// the zero value, as in a client built without a config, has the defaults
+	AssertEqual(t, DefaultEntityTypes().Filters("api"), EntityTypes{}.Filters("api"))
+}
+
+type typedPopulator struct {
+	testPopulator
+	types EntityTypes
+}
+
+func (pop *typedPopulator) GetEntityTypes() EntityTypes { return pop.types }
+
+func TestPrintWithEntityTypes(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	AssertError(t, PrintComponent(&testPopulator{name: "my-model", ref: "./"}, buffer))
+	AssertContains(t, buffer.String(), "type: "+COMPONENT_TYPE)
+
+	buffer.Reset()
+	pop := &typedPopulator{testPopulator: testPopulator{name: "my-model", ref: "./"}, types: EntityTypes{Component: []string{"llm", "model-server"}, Resource: []string{"vector-db"}}}
+	AssertError(t, PrintComponent(pop, buffer))
+	AssertError(t, PrintResource(pop, buffer))
+	AssertError(t, PrintAPI(pop, buffer))
+	AssertContains(t, buffer.String(), "type: llm")
+	AssertContains(t, buffer.String(), "type: vector-db")
+	AssertContains(t, buffer.String(), "type: "+API_TYPE)
+}
+
+func TestGetComponentEntityTypes(t *testing.T) {
+	filters := [][]string{}
+	ts := CreateTestServer(func(w http.ResponseWriter, r *http.Request) {
+		filters = append(filters, r.URL.Query()[FILTER_PARAM])
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"items":[%s]}`, fmt.Sprintf(TestEntityJSON, KindComponent, "my-model", "default"))))
+	})
+	defer ts.Close()
+
+	client := SetupBackstageTestRESTClient(ts)
+	client.Types = EntityTypes{Component: []string{"model-server", "llm"}}
+	items, err := client.GetComponent(context.Background())
+	AssertError(t, err)
+	AssertEqual(t, 1, len(items))
+
+	client.Tags, client.Subset = true, true
+	_, err = client.GetComponent(context.Background(), "genai", "vllm")
+	AssertError(t, err)
+	AssertEqual(t, [][]string{
+		{"kind=component,spec.type=model-server", "kind=component,spec.type=llm"},
+		{"kind=component,spec.type=model-server,metadata.tags=genai,metadata.tags=vllm", "kind=component,spec.type=llm,metadata.tags=genai,metadata.tags=vllm"},
+	}, filters)
+}
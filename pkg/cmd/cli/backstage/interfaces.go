@@ -2,7 +2,7 @@ package backstage
 
 import (
 	"github.com/gabemontero/backstage-ai-cli/pkg/util"
-	"github.com/spf13/cobra"
+	"io"
 	"k8s.io/klog/v2"
 )
 
@@ -34,23 +34,55 @@ type APIPopulator interface {
 	GetDependencyOf() []string
 }
 
-func PrintComponent(pop ComponentPopulator, cmd *cobra.Command) error {
+type SystemPopulator interface {
+	CommonPopulator
+}
+
+// SystemMemberPopulator is implemented by populators whose entities belong to a System
+type SystemMemberPopulator interface {
+	GetSystem() string
+}
+
+// AnnotationPopulator is implemented by populators that know the model format, registry IDs, or Kubernetes objects
+// behind their entities; empty values are left out
+type AnnotationPopulator interface {
+	GetAnnotations() map[string]string
+}
+
+// NamespacePopulator is implemented by populators whose entities go in a catalog namespace other than the default;
+// their owner is then referenced as 'user:default/<owner>', so it is not looked up in that namespace
+type NamespacePopulator interface {
+	GetNamespace() string
+}
+
+// LabelPopulator is implemented by populators with labels for their entities, beyond the source
+type LabelPopulator interface {
+	GetLabels() map[string]string
+}
+
+func PrintComponent(pop ComponentPopulator, out io.Writer) error {
 	component := &ComponentEntityV1alpha1{
 		Kind:       "Component",
 		ApiVersion: VERSION,
 		Entity:     buildEntity("Component", pop),
 	}
-	component.Entity.Metadata.Annotations = map[string]string{TECHDOC_REFS: pop.GetTechdocRef()}
+	component.Entity.Metadata.Annotations[TECHDOC_REFS] = TECHDOC_REFS_DIR_PREFIX + pop.GetTechdocRef()
 	component.Metadata = component.Entity.Metadata
 	component.Spec = &ComponentEntityV1alpha1Spec{
-		Type:         COMPONENT_TYPE,
+		Type:         getType(pop, "Component"),
 		Lifecycle:    pop.GetLifecycle(),
-		Owner:        "user:" + pop.GetOwner(),
+		Owner:        ownerRef(pop),
 		ProvidesApis: pop.GetProvidedAPIs(),
 		DependsOn:    pop.GetDependsOn(),
+		System:       getSystem(pop),
 		Profile:      Profile{DisplayName: pop.GetDisplayName()},
 	}
-	err := util.PrintYaml(component, true, cmd)
+	err := ValidateObject(component)
+	if err != nil {
+		klog.Errorf("ERROR: component %s failed validation: %s", component.Metadata.Name, err.Error())
+		return err
+	}
+	err = util.PrintYaml(component, true, out)
 	if err != nil {
 		klog.Errorf("ERROR: converting component to yaml and printing: %s, %#v", err.Error(), component)
 		return err
@@ -58,23 +90,29 @@ func PrintComponent(pop ComponentPopulator, cmd *cobra.Command) error {
 	return nil
 }
 
-func PrintResource(pop ResourcePopulator, cmd *cobra.Command) error {
+func PrintResource(pop ResourcePopulator, out io.Writer) error {
 	resource := &ResourceEntityV1alpha1{
 		Kind:       "Resource",
 		ApiVersion: VERSION,
 		Entity:     buildEntity("Resource", pop),
 	}
-	resource.Entity.Metadata.Annotations = map[string]string{TECHDOC_REFS: pop.GetTechdocRef()}
+	resource.Entity.Metadata.Annotations[TECHDOC_REFS] = TECHDOC_REFS_DIR_PREFIX + pop.GetTechdocRef()
 	resource.Metadata = resource.Entity.Metadata
 	resource.Spec = &ResourceEntityV1alpha1Spec{
-		Type:         RESOURCE_TYPE,
-		Owner:        "user:" + pop.GetOwner(),
+		Type:         getType(pop, "Resource"),
+		Owner:        ownerRef(pop),
 		Lifecycle:    pop.GetLifecycle(),
 		ProvidesApis: pop.GetProvidedAPIs(),
 		DependencyOf: pop.GetDependencyOf(),
+		System:       getSystem(pop),
 		Profile:      Profile{DisplayName: pop.GetDisplayName()},
 	}
-	err := util.PrintYaml(resource, true, cmd)
+	err := ValidateObject(resource)
+	if err != nil {
+		klog.Errorf("ERROR: resource %s failed validation: %s", resource.Metadata.Name, err.Error())
+		return err
+	}
+	err = util.PrintYaml(resource, true, out)
 	if err != nil {
 		klog.Errorf("ERROR: converting resource to yaml and printing: %s, %#v", err.Error(), resource)
 		return err
@@ -82,23 +120,32 @@ func PrintResource(pop ResourcePopulator, cmd *cobra.Command) error {
 	return nil
 }
 
-func PrintAPI(pop APIPopulator, cmd *cobra.Command) error {
+func PrintAPI(pop APIPopulator, out io.Writer) error {
 	api := &ApiEntityV1alpha1{
 		Kind:       "API",
 		ApiVersion: VERSION,
 		Entity:     buildEntity("API", pop),
 	}
-	api.Entity.Metadata.Annotations = map[string]string{TECHDOC_REFS: pop.GetTechdocRef()}
+	api.Entity.Metadata.Annotations[TECHDOC_REFS] = TECHDOC_REFS_DIR_PREFIX + pop.GetTechdocRef()
 	api.Metadata = api.Entity.Metadata
 	api.Spec = &ApiEntityV1alpha1Spec{
-		Type:         API_TYPE,
+		Type:         getType(pop, "API"),
 		Lifecycle:    pop.GetLifecycle(),
-		Owner:        "user:" + pop.GetOwner(),
+		Owner:        ownerRef(pop),
 		Definition:   pop.GetDefinition(),
 		DependencyOf: pop.GetDependencyOf(),
+		System:       getSystem(pop),
 		Profile:      Profile{DisplayName: pop.GetDisplayName()},
 	}
-	err := util.PrintYaml(api, false, cmd)
+	err := ValidateObject(api)
+	if err != nil {
+		klog.Errorf("ERROR: api %s failed validation: %s", api.Metadata.Name, err.Error())
+		return err
+	}
+	if output, ok := out.(*ModelOutput); ok {
+		output.setDefinition(api.Metadata.Name, api.Spec.Definition)
+	}
+	err = util.PrintYaml(api, true, out)
 	if err != nil {
 		klog.Errorf("ERROR: converting api to yaml and printing: %s, %#v", err.Error(), api)
 		return err
@@ -106,6 +153,48 @@ func PrintAPI(pop APIPopulator, cmd *cobra.Command) error {
 	return nil
 }
 
+func PrintSystem(pop SystemPopulator, out io.Writer) error {
+	system := &SystemEntityV1alpha1{
+		Kind:       KindSystem,
+		ApiVersion: VERSION,
+		Entity:     buildEntity(KindSystem, pop),
+	}
+	if len(pop.GetTechdocRef()) > 0 {
+		system.Entity.Metadata.Annotations[TECHDOC_REFS] = TECHDOC_REFS_DIR_PREFIX + pop.GetTechdocRef()
+	}
+	system.Metadata = system.Entity.Metadata
+	system.Spec = &SystemEntityV1alpha1Spec{
+		Type:    getType(pop, "System"),
+		Owner:   ownerRef(pop),
+		Profile: Profile{DisplayName: pop.GetDisplayName()},
+	}
+	err := ValidateObject(system)
+	if err != nil {
+		klog.Errorf("ERROR: system %s failed validation: %s", system.Metadata.Name, err.Error())
+		return err
+	}
+	err = util.PrintYaml(system, true, out)
+	if err != nil {
+		klog.Errorf("ERROR: converting system to yaml and printing: %s, %#v", err.Error(), system)
+		return err
+	}
+	return nil
+}
+
+func getSystem(pop CommonPopulator) string {
+	if member, ok := pop.(SystemMemberPopulator); ok {
+		return member.GetSystem()
+	}
+	return ""
+}
+
+func ownerRef(pop CommonPopulator) string {
+	if _, ok := pop.(NamespacePopulator); ok {
+		return Ref("user", DEFAULT_NS, pop.GetOwner())
+	}
+	return "user:" + pop.GetOwner()
+}
+
 func buildEntity(kind string, pop CommonPopulator) Entity {
 	entity := Entity{
 		Kind:       kind,
@@ -115,7 +204,33 @@ func buildEntity(kind string, pop CommonPopulator) Entity {
 			Description: pop.GetDescription(),
 			Tags:        pop.GetTags(),
 			Links:       pop.GetLinks(),
+			Annotations: map[string]string{},
 		},
 	}
+	if namespaced, ok := pop.(NamespacePopulator); ok {
+		entity.Metadata.Namespace = namespaced.GetNamespace()
+	}
+	if annotated, ok := pop.(AnnotationPopulator); ok {
+		for key, value := range annotated.GetAnnotations() {
+			if len(value) > 0 {
+				entity.Metadata.Annotations[key] = value
+			}
+		}
+	}
+	labels := map[string]string{}
+	if labeled, ok := pop.(LabelPopulator); ok {
+		for key, value := range labeled.GetLabels() {
+			if len(value) > 0 {
+				labels[key] = value
+			}
+		}
+	}
+	// the source is also a label so the catalog can be filtered by it
+	if source := entity.Metadata.Annotations[SOURCE_ANNOTATION]; len(source) > 0 {
+		labels[SOURCE_ANNOTATION] = source
+	}
+	if len(labels) > 0 {
+		entity.Metadata.Labels = labels
+	}
 	return entity
 }
@@ -0,0 +1,40 @@
+package backstage
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type annotatedPopulator struct {
+	testPopulator
+	annotations map[string]string
+}
+
+func (pop *annotatedPopulator) GetAnnotations() map[string]string { return pop.annotations }
+
+func TestPrintWithAnnotations(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	pop := &annotatedPopulator{
+		testPopulator: testPopulator{name: "my-model", ref: "./"},
+		annotations: map[string]string{
+			SOURCE_ANNOTATION:                    SOURCE_KSERVE,
+			KUBERNETES_NAMESPACE_ANNOTATION:      "my-ns",
+			KUBERNETES_LABEL_SELECTOR_ANNOTATION: KSERVE_INFERENCE_SERVICE_LABEL + "=my-model",
+			MODEL_FORMAT_VERSION_ANNOTATION:      "",
+		},
+	}
+	AssertError(t, PrintComponent(pop, buffer))
+	AssertError(t, PrintSystem(&annotatedPopulator{testPopulator: testPopulator{name: "my-ns"}, annotations: map[string]string{SOURCE_ANNOTATION: SOURCE_KSERVE}}, buffer))
+	out := buffer.String()
+	AssertContains(t, out, "backstage.io/techdocs-ref: dir:./")
+	AssertContains(t, out, "backstage.io/kubernetes-namespace: my-ns")
+	AssertContains(t, out, "backstage.io/kubernetes-label-selector: serving.kserve.io/inferenceservice=my-model")
+	AssertContains(t, out, "labels:\n    ai.backstage.io/source: kserve")
+	AssertEqual(t, false, strings.Contains(out, MODEL_FORMAT_VERSION_ANNOTATION))
+	AssertEqual(t, 4, strings.Count(out, "ai.backstage.io/source: kserve"))
+
+	buffer.Reset()
+	AssertError(t, PrintComponent(&testPopulator{name: "my-model", ref: "./"}, buffer))
+	AssertEqual(t, false, strings.Contains(buffer.String(), "labels:"))
+}
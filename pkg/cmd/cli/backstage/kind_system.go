@@ -0,0 +1,36 @@
+package backstage
+
+// KindSystem defines name for system kind.
+const KindSystem = "System"
+
+// SystemEntityV1alpha1 is a collection of resources and components. The system may expose or consume one or several APIs.
+// It is viewed as abstraction level that provides potential consumers insights into exposed features without needing a
+// too detailed view into the details of all components.
+// https://github.com/backstage/backstage/blob/master/packages/catalog-model/src/schema/kinds/System.v1alpha1.schema.json
+type SystemEntityV1alpha1 struct {
+	Entity
+
+	// ApiVersion is always "backstage.io/v1alpha1".
+	ApiVersion string `json:"apiVersion" yaml:"apiVersion"`
+
+	// Kind is always "System".
+	Kind string `json:"kind" yaml:"kind"`
+
+	// Spec is the specification data describing the system itself.
+	Spec *SystemEntityV1alpha1Spec `json:"spec" yaml:"spec"`
+}
+
+// SystemEntityV1alpha1Spec describes the specification data describing the system itself.
+type SystemEntityV1alpha1Spec struct {
+	// Type of system.
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+
+	// Owner is an entity reference to the owner of the system.
+	Owner string `json:"owner" yaml:"owner"`
+
+	// Domain is an entity reference to the domain that the system belongs to.
+	Domain string `json:"domain,omitempty" yaml:"domain,omitempty"`
+
+	//FIX from schema
+	Profile Profile `json:"profile" yaml:"profile"`
+}
@@ -1,42 +1,172 @@
 package backstage
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
+	"fmt"
 )
 
-func (b *BackstageRESTClientWrapper) ListLocations() (string, error) {
-	str, err := b.getFromBackstage(b.RootURL + LOCATION_URI)
+// Location is a Backstage catalog location, the source the catalog reads entities from
+type Location struct {
+	ID     string `json:"id,omitempty" yaml:"id,omitempty"`
+	Type   string `json:"type" yaml:"type"`
+	Target string `json:"target" yaml:"target"`
+}
+
+// ImportResult is the location Backstage registered, or would register on a dry run, along with the entities read from it
+type ImportResult struct {
+	Location Location `json:"location" yaml:"location"`
+	Entities []Entity `json:"entities" yaml:"entities"`
+	Exists   bool     `json:"exists,omitempty" yaml:"exists,omitempty"`
+}
+
+// LocationAnalysis is what Backstage found at a location URL, the entity files that exist there and the entities it
+// would need to generate
+type LocationAnalysis struct {
+	ExistingEntityFiles []ExistingEntityFile `json:"existingEntityFiles" yaml:"existingEntityFiles"`
+	GenerateEntities    []GenerateEntity     `json:"generateEntities" yaml:"generateEntities"`
+}
+
+type ExistingEntityFile struct {
+	Location     Location `json:"location" yaml:"location"`
+	IsRegistered bool     `json:"isRegistered" yaml:"isRegistered"`
+	Entity       Entity   `json:"entity" yaml:"entity"`
+}
+
+type GenerateEntity struct {
+	Entity Entity `json:"entity" yaml:"entity"`
+}
+
+// locationEntry is the wrapper Backstage puts around each location when listing them
+type locationEntry struct {
+	Data Location `json:"data"`
+}
+
+func (b *BackstageRESTClientWrapper) ListLocations(ctx context.Context) ([]Location, error) {
+	buf, err := b.getFromBackstage(ctx, b.RootURL+LOCATION_URI)
+	if err != nil {
+		return nil, err
+	}
+	entries := []locationEntry{}
+	err = unmarshal(buf, &entries)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	locations := []Location{}
+	for _, entry := range entries {
+		locations = append(locations, entry.Data)
 	}
-	buf := []byte(str)
-	buffer := &bytes.Buffer{}
-	err = json.Indent(buffer, buf, "", "    ")
-	return buffer.String(), err
+	return locations, nil
 }
 
-func (b *BackstageRESTClientWrapper) GetLocation(args ...string) (string, error) {
+func (b *BackstageRESTClientWrapper) GetLocation(ctx context.Context, args ...string) ([]Location, error) {
 	if len(args) == 0 {
-		return b.ListLocations()
+		return b.ListLocations(ctx)
 	}
-	buffer := &bytes.Buffer{}
+	locations := []Location{}
 	for _, id := range args {
-		str, err := b.getFromBackstage(b.RootURL + LOCATION_URI + "/" + id)
+		buf, err := b.getFromBackstage(ctx, b.RootURL+LOCATION_URI+"/"+id)
+		if err != nil {
+			return locations, err
+		}
+		location := Location{}
+		err = unmarshal(buf, &location)
 		if err != nil {
-			return buffer.String(), err
+			return locations, err
 		}
-		buf := []byte(str)
-		err = json.Indent(buffer, buf, "", "    ")
-		buffer.WriteString("\n")
+		locations = append(locations, location)
 	}
-	return buffer.String(), nil
+	return locations, nil
 }
 
-func (b *BackstageRESTClientWrapper) ImportLocation(url string) (string, error) {
-	return b.postToBackstage(b.RootURL+LOCATION_URI, map[string]interface{}{"target": url, "type": "url"})
+func (b *BackstageRESTClientWrapper) ImportLocation(ctx context.Context, url string) (*ImportResult, error) {
+	return b.RegisterLocation(ctx, Location{Target: url, Type: "url"})
 }
 
-func (b *BackstageRESTClientWrapper) DeleteLocation(id string) (string, error) {
-	return b.deleteFromBackstage(b.RootURL + LOCATION_URI + "/" + id)
+// RegisterLocation has Backstage register a location of any type, such as a 'file' location, whose target is a path
+// on the host Backstage runs on
+func (b *BackstageRESTClientWrapper) RegisterLocation(ctx context.Context, location Location) (*ImportResult, error) {
+	buf, err := b.postToBackstage(ctx, b.RootURL+LOCATION_URI, location)
+	if err != nil {
+		return nil, err
+	}
+	result := &ImportResult{}
+	err = unmarshal(buf, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (b *BackstageRESTClientWrapper) DeleteLocation(ctx context.Context, id string) error {
+	return b.deleteFromBackstage(ctx, b.RootURL+LOCATION_URI+"/"+id)
+}
+
+// GetLocationByTarget finds the location imported from the target URL, as Backstage only looks up locations by their
+// generated ID
+func (b *BackstageRESTClientWrapper) GetLocationByTarget(ctx context.Context, target string) (*Location, error) {
+	locations, err := b.ListLocations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, location := range locations {
+		if location.Target == target {
+			return &location, nil
+		}
+	}
+	return nil, fmt.Errorf("no Backstage location with the target %s", target)
+}
+
+// DryRunImportLocation has Backstage read and process the entities at the URL without registering the location
+func (b *BackstageRESTClientWrapper) DryRunImportLocation(ctx context.Context, url string) (*ImportResult, error) {
+	rc, buf, err := b.postForResult(ctx, b.RootURL+LOCATION_URI+"?"+DRY_RUN_PARAM+"=true", Location{Target: url, Type: "url"})
+	if err != nil {
+		return nil, err
+	}
+	if rc == 400 {
+		return nil, processorError(url, buf)
+	}
+	result := &ImportResult{}
+	err = unmarshal(buf, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// AnalyzeLocation has Backstage report on the entity files found at the URL and whether they are already registered
+func (b *BackstageRESTClientWrapper) AnalyzeLocation(ctx context.Context, url string) (*LocationAnalysis, error) {
+	rc, buf, err := b.postForResult(ctx, b.RootURL+ANALYZE_URI, map[string]interface{}{"location": Location{Type: "url", Target: url}})
+	if err != nil {
+		return nil, err
+	}
+	if rc == 400 {
+		return nil, processorError(url, buf)
+	}
+	analysis := &LocationAnalysis{}
+	err = unmarshal(buf, analysis)
+	if err != nil {
+		return nil, err
+	}
+	return analysis, nil
+}
+
+func processorError(url string, buf []byte) error {
+	resp := &errorResponse{}
+	if err := unmarshal(buf, resp); err != nil || len(resp.Error.Message) == 0 {
+		return fmt.Errorf("Backstage rejected location %s: %s", url, string(buf))
+	}
+	return fmt.Errorf("Backstage rejected location %s: %s: %s", url, resp.Error.Name, resp.Error.Message)
+}
+
+// EntityRef is the 'kind:namespace/name' reference to the entity, filling in the default namespace
+func EntityRef(entity Entity) string {
+	return Ref(entity.Kind, entity.Metadata.Namespace, entity.Metadata.Name)
+}
+
+// Ref is the full 'kind:namespace/name' form of an entity reference, filling in the default namespace
+func Ref(kind, namespace, name string) string {
+	if len(namespace) == 0 {
+		namespace = DEFAULT_NS
+	}
+	return fmt.Sprintf("%s:%s/%s", kind, namespace, name)
 }
@@ -1,16 +1,22 @@
 package backstage
 
 import (
+	"context"
+	"encoding/json"
+	"github.com/gabemontero/backstage-ai-cli/pkg/config"
+	"github.com/gabemontero/backstage-ai-cli/pkg/util"
+	"net/http"
 	"testing"
+	"time"
 )
 
 func TestListLocations(t *testing.T) {
 	ts := CreateServer(t)
 	defer ts.Close()
 
-	str, err := SetupBackstageTestRESTClient(ts).ListLocations()
+	locations, err := SetupBackstageTestRESTClient(ts).ListLocations(context.Background())
 	AssertError(t, err)
-	AssertEqual(t, TestJSONStringIndented, str)
+	AssertEqual(t, []Location{{ID: "key1", Type: "url", Target: "https://my-repo/key1.yaml"}, {ID: "key2", Type: "url", Target: "https://my-repo/key2.yaml"}}, locations)
 }
 
 func TestGetLocations(t *testing.T) {
@@ -18,9 +24,9 @@ func TestGetLocations(t *testing.T) {
 	defer ts.Close()
 
 	key := "key1"
-	str, err := SetupBackstageTestRESTClient(ts).GetLocation(key)
+	locations, err := SetupBackstageTestRESTClient(ts).GetLocation(context.Background(), key)
 	AssertError(t, err)
-	AssertContains(t, str, key)
+	AssertEqual(t, []Location{{ID: key, Type: "url", Target: "https://my-repo/key1.yaml"}}, locations)
 }
 
 func TestGetLocationsError(t *testing.T) {
@@ -28,7 +34,7 @@ func TestGetLocationsError(t *testing.T) {
 	defer ts.Close()
 
 	nsName := "404:404"
-	_, err := SetupBackstageTestRESTClient(ts).GetLocation(nsName)
+	_, err := SetupBackstageTestRESTClient(ts).GetLocation(context.Background(), nsName)
 	if err == nil {
 		t.Error("expected error")
 	}
@@ -39,9 +45,54 @@ func TestImportLocation(t *testing.T) {
 	defer ts.Close()
 
 	arg := "https://my-repo/my.yaml"
-	str, err := SetupBackstageTestRESTClient(ts).ImportLocation(arg)
+	result, err := SetupBackstageTestRESTClient(ts).ImportLocation(context.Background(), arg)
 	AssertError(t, err)
-	AssertContains(t, str, arg)
+	AssertEqual(t, Location{ID: "my-location-id", Type: "url", Target: arg}, result.Location)
+	AssertEqual(t, 1, len(result.Entities))
+	AssertEqual(t, "Component:default/my-component", EntityRef(result.Entities[0]))
+}
+
+func TestRegisterLocation(t *testing.T) {
+	ts := CreateTestServer(func(w http.ResponseWriter, r *http.Request) {
+		location := Location{}
+		err := json.NewDecoder(r.Body).Decode(&location)
+		AssertError(t, err)
+		AssertEqual(t, Location{Type: "file", Target: "/backup/my-model/catalog-info.yaml"}, location)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(201)
+		_, _ = w.Write([]byte(`{"location":{"id":"my-location-id","type":"file","target":"/backup/my-model/catalog-info.yaml"},"entities":[]}`))
+	})
+	defer ts.Close()
+
+	result, err := SetupBackstageTestRESTClient(ts).RegisterLocation(context.Background(), Location{Type: "file", Target: "/backup/my-model/catalog-info.yaml"})
+	AssertError(t, err)
+	AssertEqual(t, "my-location-id", result.Location.ID)
+	AssertEqual(t, "file", result.Location.Type)
+}
+
+func TestImportLocationRetries(t *testing.T) {
+	for _, tc := range []struct {
+		status int
+		calls  int
+	}{
+		// the location may have been registered before the server failed, so the POST is not repeated
+		{status: http.StatusServiceUnavailable, calls: 1},
+		{status: http.StatusTooManyRequests, calls: 3},
+	} {
+		calls := 0
+		ts := CreateTestServer(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(tc.status)
+		})
+		client := SetupBackstageTestRESTClient(ts)
+		util.ConfigureRESTClient(client.RESTClient, &config.Config{Retries: 2, RetryWait: time.Millisecond, RetryMaxWait: 10 * time.Millisecond})
+		_, err := client.ImportLocation(context.Background(), "https://my-repo/my.yaml")
+		if err == nil {
+			t.Errorf("expected an error for status %d", tc.status)
+		}
+		AssertEqual(t, tc.calls, calls)
+		ts.Close()
+	}
 }
 
 func TestImportLocationError(t *testing.T) {
@@ -49,7 +100,7 @@ func TestImportLocationError(t *testing.T) {
 	defer ts.Close()
 
 	arg := ":"
-	_, err := SetupBackstageTestRESTClient(ts).ImportLocation(arg)
+	_, err := SetupBackstageTestRESTClient(ts).ImportLocation(context.Background(), arg)
 	if err == nil {
 		t.Error("expected error")
 	}
@@ -60,9 +111,7 @@ func TestDeleteLocation(t *testing.T) {
 	defer ts.Close()
 
 	arg := "my-location-id"
-	str, err := SetupBackstageTestRESTClient(ts).DeleteLocation(arg)
-	AssertError(t, err)
-	AssertContains(t, str, arg)
+	AssertError(t, SetupBackstageTestRESTClient(ts).DeleteLocation(context.Background(), arg))
 }
 
 func TestDeleteLocationsError(t *testing.T) {
@@ -70,7 +119,70 @@ func TestDeleteLocationsError(t *testing.T) {
 	defer ts.Close()
 
 	nsName := "404:404"
-	_, err := SetupBackstageTestRESTClient(ts).DeleteLocation(nsName)
+	err := SetupBackstageTestRESTClient(ts).DeleteLocation(context.Background(), nsName)
+	if err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestDryRunImportLocation(t *testing.T) {
+	ts := CreateServer(t)
+	defer ts.Close()
+
+	result, err := SetupBackstageTestRESTClient(ts).DryRunImportLocation(context.Background(), "https://my-repo/my.yaml")
+	AssertError(t, err)
+	AssertEqual(t, false, result.Exists)
+	AssertEqual(t, "https://my-repo/my.yaml", result.Location.Target)
+	AssertEqual(t, 2, len(result.Entities))
+	AssertEqual(t, "Component:default/my-component", EntityRef(result.Entities[0]))
+	AssertEqual(t, "API:ai/my-api", EntityRef(result.Entities[1]))
+}
+
+func TestDryRunImportLocationError(t *testing.T) {
+	ts := CreateServer(t)
+	defer ts.Close()
+
+	_, err := SetupBackstageTestRESTClient(ts).DryRunImportLocation(context.Background(), "https://my-repo/invalid.yaml")
+	if err == nil {
+		t.Error("expected error")
+		return
+	}
+	AssertContains(t, err.Error(), "Many errors occurred")
+}
+
+func TestAnalyzeLocation(t *testing.T) {
+	ts := CreateServer(t)
+	defer ts.Close()
+
+	analysis, err := SetupBackstageTestRESTClient(ts).AnalyzeLocation(context.Background(), "https://my-repo/my.yaml")
+	AssertError(t, err)
+	AssertEqual(t, 1, len(analysis.ExistingEntityFiles))
+	AssertEqual(t, 0, len(analysis.GenerateEntities))
+	file := analysis.ExistingEntityFiles[0]
+	AssertEqual(t, "Component:default/my-component", EntityRef(file.Entity))
+	AssertEqual(t, Location{Type: "url", Target: "https://my-repo/my.yaml"}, file.Location)
+	AssertEqual(t, true, file.IsRegistered)
+}
+
+func TestAnalyzeLocationError(t *testing.T) {
+	ts := CreateServer(t)
+	defer ts.Close()
+
+	_, err := SetupBackstageTestRESTClient(ts).AnalyzeLocation(context.Background(), "https://my-repo/invalid.yaml")
+	if err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestGetLocationByTarget(t *testing.T) {
+	ts := CreateServer(t)
+	defer ts.Close()
+
+	location, err := SetupBackstageTestRESTClient(ts).GetLocationByTarget(context.Background(), "https://my-repo/key2.yaml")
+	AssertError(t, err)
+	AssertEqual(t, "key2", location.ID)
+
+	_, err = SetupBackstageTestRESTClient(ts).GetLocationByTarget(context.Background(), "https://my-repo/unknown.yaml")
 	if err == nil {
 		t.Error("expected error")
 	}
@@ -0,0 +1,41 @@
+package backstage
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// RefreshEntity has Backstage schedule the entity for processing right away, rather than on its next processing loop
+func (b *BackstageRESTClientWrapper) RefreshEntity(ctx context.Context, ref string) error {
+	kind, namespace, name, err := ParseEntityRef(ref)
+	if err != nil {
+		return err
+	}
+	_, err = b.postToBackstage(ctx, b.RootURL+REFRESH_URI, map[string]interface{}{"entityRef": kind + ":" + namespace + "/" + name})
+	return err
+}
+
+// WaitForRefresh polls the entity until its etag or processing status differs from the provided entity, as fetched
+// before the refresh was requested, returning the updated entity.  The context bounds how long to wait.
+func WaitForRefresh(ctx context.Context, client CatalogClient, ref string, before *Entity, interval time.Duration) (*Entity, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+		entity, err := client.GetEntity(ctx, ref)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err != nil {
+			return nil, err
+		}
+		if before == nil || entity.Metadata.Etag != before.Metadata.Etag || !reflect.DeepEqual(entity.Status, before.Status) {
+			return entity, nil
+		}
+	}
+}
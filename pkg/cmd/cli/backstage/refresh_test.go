@@ -0,0 +1,114 @@
+package backstage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseEntityRef(t *testing.T) {
+	for _, tc := range []struct {
+		ref       string
+		kind      string
+		namespace string
+		name      string
+		err       bool
+	}{
+		{ref: "component:default/my-model", kind: "component", namespace: "default", name: "my-model"},
+		{ref: "API:ai/my-api", kind: "api", namespace: "ai", name: "my-api"},
+		{ref: "resource:my-model", kind: "resource", namespace: DEFAULT_NS, name: "my-model"},
+		{ref: "my-model", err: true},
+		{ref: "component:", err: true},
+		{ref: "component:ns/", err: true},
+	} {
+		kind, namespace, name, err := ParseEntityRef(tc.ref)
+		if tc.err {
+			if err == nil {
+				t.Errorf("%s: expected an error", tc.ref)
+			}
+			continue
+		}
+		AssertError(t, err)
+		AssertEqual(t, tc.kind, kind)
+		AssertEqual(t, tc.namespace, namespace)
+		AssertEqual(t, tc.name, name)
+	}
+}
+
+func TestRefreshEntity(t *testing.T) {
+	refreshed := []string{}
+	ts := CreateTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != MethodPost || r.URL.Path != REFRESH_URI {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body := map[string]string{}
+		buf, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(buf, &body)
+		if strings.Contains(body["entityRef"], "404") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		refreshed = append(refreshed, body["entityRef"])
+		w.WriteHeader(http.StatusOK)
+	})
+	defer ts.Close()
+
+	bs := SetupBackstageTestRESTClient(ts)
+	AssertError(t, bs.RefreshEntity(context.Background(), "Component:my-model"))
+	AssertEqual(t, []string{"component:default/my-model"}, refreshed)
+	if err := bs.RefreshEntity(context.Background(), "component:404/404"); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestGetLocationEntities(t *testing.T) {
+	ts := CreateTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get(CURSOR_PARAM) {
+		case "":
+			AssertEqual(t, "metadata.annotations.backstage.io/managed-by-location=url:https://my-repo/my.yaml", r.URL.Query().Get(FILTER_PARAM))
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"items":[%s],"pageInfo":{"nextCursor":"page2"}}`, fmt.Sprintf(TestEntityJSON, KindComponent, "my-model", "default"))))
+		case "page2":
+			AssertEqual(t, "", r.URL.Query().Get(FILTER_PARAM))
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"items":[%s],"pageInfo":{}}`, fmt.Sprintf(TestEntityJSON, KindAPI, "my-model", "default"))))
+		}
+	})
+	defer ts.Close()
+
+	entities, err := SetupBackstageTestRESTClient(ts).GetLocationEntities(context.Background(), Location{Type: "url", Target: "https://my-repo/my.yaml"})
+	AssertError(t, err)
+	AssertEqual(t, 2, len(entities))
+	AssertEqual(t, "Component:default/my-model", EntityRef(entities[0]))
+	AssertEqual(t, "API:default/my-model", EntityRef(entities[1]))
+}
+
+func TestWaitForRefresh(t *testing.T) {
+	gets := int32(0)
+	ts := CreateTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		etag := "before"
+		if atomic.AddInt32(&gets, 1) > 2 && !strings.Contains(r.URL.Path, "stuck") {
+			etag = "after"
+		}
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"kind":"Component","metadata":{"name":"my-model","etag":"%s"}}`, etag)))
+	})
+	defer ts.Close()
+
+	bs := SetupBackstageTestRESTClient(ts)
+	before := &Entity{Metadata: EntityMeta{Etag: "before"}}
+	entity, err := WaitForRefresh(context.Background(), bs, "component:default/my-model", before, time.Millisecond)
+	AssertError(t, err)
+	AssertEqual(t, "after", entity.Metadata.Etag)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = WaitForRefresh(ctx, bs, "component:default/stuck", before, time.Millisecond)
+	AssertEqual(t, context.DeadlineExceeded, err)
+}
@@ -1,9 +1,7 @@
 package backstage
 
 import (
-	"bytes"
-	"encoding/json"
-	"fmt"
+	"context"
 	"net/url"
 )
 
@@ -13,21 +11,19 @@ type listResources struct {
 	PageInfo   interface{}              `json:"pageInfo" yaml:"pageInfo"`
 }
 
-func (b *BackstageRESTClientWrapper) ListResources(qparms *url.Values) (string, error) {
+func (b *BackstageRESTClientWrapper) ListResources(ctx context.Context, qparms *url.Values) ([]ResourceEntityV1alpha1, error) {
 	//TODO remove this post query filter logic if an exact query parameter check for the 'metadata.tags' array is determined
 	argsArr := b.pullSavedArgsFromQueryParams(qparms)
 
-	str, err := b.getWithKindParamFromBackstage(b.RootURL+QUERY_URI, qparms)
+	buf, err := b.getWithKindParamFromBackstage(ctx, b.RootURL+QUERY_URI, qparms)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	buf := []byte(str)
-
 	lr := &listResources{}
-	err = json.Unmarshal(buf, lr)
+	err = unmarshal(buf, lr)
 	if err != nil {
-		return str, err
+		return nil, err
 	}
 
 	//TODO remove this post query filter logic if an exact query parameter check for the 'metadata.tags' array is determined
@@ -41,38 +37,32 @@ func (b *BackstageRESTClientWrapper) ListResources(qparms *url.Values) (string,
 		lr.Items = filteredResources
 	}
 
-	buf, err = json.MarshalIndent(lr.Items, "", "    ")
-	return string(buf), err
+	return lr.Items, nil
 }
 
-func (b *BackstageRESTClientWrapper) GetResource(args ...string) (string, error) {
+func (b *BackstageRESTClientWrapper) GetResource(ctx context.Context, args ...string) ([]ResourceEntityV1alpha1, error) {
 	// example 'filter' value from swagger doc:  'kind=component,metadata.annotations.backstage.io/orphan=true'
-	filterValue := "kind=resource,spec.type=ai-model"
+	// with a filter for each of the kind's types, which Backstage ORs
+	filterValues := b.Types.Filters("resource")
 	qparams := &url.Values{
-		"filter": []string{filterValue},
+		"filter": filterValues,
 	}
 	if len(args) == 0 {
-		return b.ListResources(qparams)
+		return b.ListResources(ctx, qparams)
 	}
 
 	if b.Tags {
-		qparams = updateQParams(b.Subset, filterValue, args, qparams)
-		return b.ListResources(qparams)
-	}
-
-	keys := buildKeys(args...)
-	buffer := &bytes.Buffer{}
-	for namespace, names := range keys {
-		for _, name := range names {
-			str, err := b.getFromBackstage(b.RootURL + fmt.Sprintf(RESOURCE_URI, namespace, name))
-			if err != nil {
-				return buffer.String(), err
-			}
-			buf := []byte(str)
-			err = json.Indent(buffer, buf, "", "    ")
-			buffer.WriteString("\n")
-		}
+		qparams = updateQParams(b.Subset, filterValues, args, qparams)
+		return b.ListResources(ctx, qparams)
 	}
 
-	return buffer.String(), nil
+	// the entities found are returned even when some of the args are not found
+	resources := []ResourceEntityV1alpha1{}
+	err := b.getEntitiesByRefs(ctx, "resource", args, func(buf []byte) error {
+		resource := ResourceEntityV1alpha1{}
+		err := unmarshal(buf, &resource)
+		resources = append(resources, resource)
+		return err
+	})
+	return resources, err
 }
@@ -1,15 +1,18 @@
 package backstage
 
-import "testing"
+import (
+	"context"
+	"testing"
+)
 
 func TestListResources(t *testing.T) {
 	ts := CreateServer(t)
 	defer ts.Close()
 
 	// Get with no args calls List
-	str, err := SetupBackstageTestRESTClient(ts).GetResource()
+	items, err := SetupBackstageTestRESTClient(ts).GetResource(context.Background())
 	AssertError(t, err)
-	AssertLineCompare(t, str, resources, 0)
+	AssertLineCompare(t, toJSON(t, items), resources, 0)
 }
 
 func TestGetResources(t *testing.T) {
@@ -17,21 +20,25 @@ func TestGetResources(t *testing.T) {
 	defer ts.Close()
 
 	nsName := "default:phi-mini-instruct"
-	str, err := SetupBackstageTestRESTClient(ts).GetResource(nsName)
-
+	items, err := SetupBackstageTestRESTClient(ts).GetResource(context.Background(), nsName)
 	AssertError(t, err)
-	AssertContains(t, str, nsName)
+	AssertEqual(t, 1, len(items))
+	AssertEqual(t, nsName, items[0].Metadata.Namespace+":"+items[0].Metadata.Name)
 }
 
 func TestGetResourceError(t *testing.T) {
 	ts := CreateServer(t)
 	defer ts.Close()
 
-	nsName := "404:404"
-	_, err := SetupBackstageTestRESTClient(ts).GetResource(nsName)
-	if err == nil {
-		t.Error("expected error")
+	items, err := SetupBackstageTestRESTClient(ts).GetResource(context.Background(), "404:404", "default:found", "404:also-missing")
+	notFound, ok := err.(*NotFoundError)
+	if !ok {
+		t.Fatalf("expected a not found error but got %v", err)
 	}
+	AssertEqual(t, []string{"resource:404/404", "resource:404/also-missing"}, notFound.Refs)
+	AssertContains(t, err.Error(), "resource:404/404: not found")
+	AssertEqual(t, 1, len(items))
+	AssertEqual(t, "found", items[0].Metadata.Name)
 }
 
 func TestGetResourceWithTags(t *testing.T) {
@@ -60,9 +67,9 @@ func TestGetResourceWithTags(t *testing.T) {
 		},
 	} {
 		bs.Subset = tc.subset
-		str, err := bs.GetResource(tc.args...)
+		items, err := bs.GetResource(context.Background(), tc.args...)
 		AssertError(t, err)
-		AssertLineCompare(t, str, tc.str, 0)
+		AssertLineCompare(t, toJSON(t, items), tc.str, 0)
 	}
 }
 
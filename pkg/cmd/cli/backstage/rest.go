@@ -1,10 +1,12 @@
 package backstage
 
 import (
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"github.com/gabemontero/backstage-ai-cli/pkg/config"
+	"github.com/gabemontero/backstage-ai-cli/pkg/util"
 	"github.com/go-resty/resty/v2"
 	"k8s.io/klog/v2"
 	nurl "net/url"
@@ -12,151 +14,193 @@ import (
 )
 
 const (
-	BASE_URI      = "/api/catalog"
-	LOCATION_URI  = "/locations"
-	ENTITIES_URI  = "/entities"
-	COMPONENT_URI = "/entities/by-name/component/%s/%s"
-	RESOURCE_URI  = "/entities/by-name/resource/%s/%s"
-	API_URI       = "/entities/by-name/api/%s/%s"
-	QUERY_URI     = "/entities/by-query"
-	DEFAULT_NS    = "default"
+	BASE_URI       = "/api/catalog"
+	LOCATION_URI   = "/locations"
+	ENTITIES_URI   = "/entities"
+	BY_REFS_URI    = "/entities/by-refs"
+	FACETS_URI     = "/entity-facets"
+	ENTITY_URI     = "/entities/by-name/%s/%s/%s"
+	ANCESTRY_URI   = "/entities/by-name/%s/%s/%s/ancestry"
+	ENTITY_UID_URI = "/entities/by-uid/%s"
+	QUERY_URI      = "/entities/by-query"
+	REFRESH_URI    = "/refresh"
+	VALIDATE_URI   = "/validate-entity"
+	ANALYZE_URI    = "/analyze-location"
+	DRY_RUN_PARAM  = "dryRun"
+	FILTER_PARAM   = "filter"
+	CURSOR_PARAM   = "cursor"
+	FACET_PARAM    = "facet"
+	DEFAULT_NS     = "default"
+
+	MANAGED_BY_LOCATION_ANNOTATION = "backstage.io/managed-by-location"
+	ORPHAN_ANNOTATION              = "backstage.io/orphan"
+	MANAGED_BY_ORIGIN_ANNOTATION   = "backstage.io/managed-by-origin-location"
+	VIEW_URL_ANNOTATION            = "backstage.io/view-url"
+	EDIT_URL_ANNOTATION            = "backstage.io/edit-url"
+	SOURCE_LOCATION_ANNOTATION     = "backstage.io/source-location"
 )
 
+// CatalogClient is the set of Backstage catalog operations the CLI uses; BackstageRESTClientWrapper is the REST
+// implementation, and callers embedding the CLI or testing it can provide their own
+type CatalogClient interface {
+	ListEntities(ctx context.Context) ([]Entity, error)
+	ValidateEntityRemote(ctx context.Context, entity map[string]interface{}, location string) ([]error, error)
+	ListLocations(ctx context.Context) ([]Location, error)
+	GetLocation(ctx context.Context, args ...string) ([]Location, error)
+	ImportLocation(ctx context.Context, url string) (*ImportResult, error)
+	RegisterLocation(ctx context.Context, location Location) (*ImportResult, error)
+	DeleteLocation(ctx context.Context, id string) error
+	GetLocationByTarget(ctx context.Context, target string) (*Location, error)
+	DryRunImportLocation(ctx context.Context, url string) (*ImportResult, error)
+	AnalyzeLocation(ctx context.Context, url string) (*LocationAnalysis, error)
+	ListComponents(ctx context.Context, qparms *nurl.Values) ([]ComponentEntityV1alpha1, error)
+	GetComponent(ctx context.Context, args ...string) ([]ComponentEntityV1alpha1, error)
+	ListResources(ctx context.Context, qparms *nurl.Values) ([]ResourceEntityV1alpha1, error)
+	GetResource(ctx context.Context, args ...string) ([]ResourceEntityV1alpha1, error)
+	ListAPIs(ctx context.Context, qparms *nurl.Values) ([]ApiEntityV1alpha1, error)
+	GetAPI(ctx context.Context, args ...string) ([]ApiEntityV1alpha1, error)
+	GetEntity(ctx context.Context, ref string) (*Entity, error)
+	GetEntityAncestry(ctx context.Context, ref string) (*EntityAncestry, error)
+	QueryEntities(ctx context.Context, filters ...string) ([]Entity, error)
+	GetEntityFacets(ctx context.Context, facets []string, filters ...string) (map[string][]FacetCount, error)
+	GetLocationEntities(ctx context.Context, location Location) ([]Entity, error)
+	RefreshEntity(ctx context.Context, ref string) error
+	DeleteEntity(ctx context.Context, ref string) (*Entity, error)
+	DeleteEntityByUID(ctx context.Context, uid string) error
+}
+
+var _ CatalogClient = &BackstageRESTClientWrapper{}
+
 type BackstageRESTClientWrapper struct {
 	RESTClient *resty.Client
 	RootURL    string
 	Token      string
 	Tags       bool
 	Subset     bool
+	Types      EntityTypes
 }
 
-var backstageRESTClient = &BackstageRESTClientWrapper{}
-
-func init() {
-	backstageRESTClient.RESTClient = resty.New()
-	if backstageRESTClient == nil {
-		klog.Errorf("Unable to get Backstage REST client wrapper")
-		os.Exit(1)
-	}
-}
-
+// SetupBackstageRESTClient returns a new client for the Backstage instance in the config; each call returns its own
+// client, so clients for different configs can be used side by side.  A REST client set in the config is used as is.
 func SetupBackstageRESTClient(cfg *config.Config) *BackstageRESTClientWrapper {
 	if cfg == nil {
 		klog.Error("Command config is nil")
+		klog.Flush()
 		os.Exit(1)
 	}
+	backstageRESTClient := &BackstageRESTClientWrapper{
+		RESTClient: cfg.BackstageRESTClient,
+		Token:      cfg.BackstageToken,
+		RootURL:    cfg.BackstageURL + BASE_URI,
+		Tags:       cfg.ParamsAsTags,
+		Subset:     cfg.AnySubsetWorks,
+		Types:      NewEntityTypes(cfg),
+	}
+	if backstageRESTClient.RESTClient != nil {
+		return backstageRESTClient
+	}
+
 	tlsCfg := &tls.Config{}
 	if cfg.BackstageSkipTLS {
 		tlsCfg.InsecureSkipVerify = true
 	}
+	backstageRESTClient.RESTClient = util.ConfigureRESTClient(resty.New(), cfg)
 	backstageRESTClient.RESTClient.SetTLSClientConfig(tlsCfg)
-	backstageRESTClient.Token = cfg.BackstageToken
-	backstageRESTClient.RootURL = cfg.BackstageURL + BASE_URI
-
-	backstageRESTClient.Tags = cfg.ParamsAsTags
-	backstageRESTClient.Subset = cfg.AnySubsetWorks
-
 	return backstageRESTClient
 }
 
-func (k *BackstageRESTClientWrapper) processUpdate(resp *resty.Response, action, url, body string) (string, error) {
-	postResp := resp.String()
+func (k *BackstageRESTClientWrapper) postToBackstage(ctx context.Context, url string, body interface{}) ([]byte, error) {
+	resp, err := k.RESTClient.R().SetContext(ctx).SetAuthToken(k.Token).SetBody(body).SetHeader("Accept", "application/json").Post(url)
+	if err != nil {
+		return nil, err
+	}
 	rc := resp.StatusCode()
-	if rc != 200 && rc != 201 {
-		return "", fmt.Errorf("%s %s with body %s status code %d resp: %s\n", url, action, body, rc, postResp)
-	} else {
-		klog.V(4).Infof("%s %s with body %s status code %d resp: %s\n", url, action, body, rc, postResp)
+	if rc != 200 && rc != 201 && rc != 204 {
+		return nil, fmt.Errorf("%s post with body %#v status code %d resp: %s\n", url, body, rc, resp.String())
 	}
-	return k.processBody(resp)
+	klog.V(4).Infof("%s post with body %#v status code %d resp: %s\n", url, body, rc, resp.String())
+	return resp.Body(), nil
 }
 
-func (k *BackstageRESTClientWrapper) processBody(resp *resty.Response) (string, error) {
-	retJSON := make(map[string]any)
-	err := json.Unmarshal(resp.Body(), &retJSON)
-	if err != nil {
-		return "", fmt.Errorf("json unmarshall error for %s: %s\n", resp.Body(), err.Error())
-	}
-	var location interface{}
-	var id interface{}
-	var target interface{}
-	var ok bool
-	location, ok = retJSON["location"]
-	if ok {
-		locationMap, o1 := location.(map[string]interface{})
-		if o1 {
-			id = locationMap["id"]
-			target = locationMap["target"]
-		}
-		return fmt.Sprintf("Backstage location %s from %s created", id, target), nil
-	}
-	id, ok = retJSON["id"]
-	if ok {
-		target, ok = retJSON["target"]
-		if ok {
-			return fmt.Sprintf("Backstage location %s from %s created", id, target), nil
-		}
-		return fmt.Sprintf("Backstage location %s created", id), nil
-	}
-	return fmt.Sprintf("%#v", retJSON), nil
+// SerializedError is the JSON form Backstage uses for errors in its REST responses
+type SerializedError struct {
+	Name    string `json:"name" yaml:"name"`
+	Message string `json:"message" yaml:"message"`
+}
+
+type errorResponse struct {
+	Error SerializedError `json:"error"`
 }
 
-func (k *BackstageRESTClientWrapper) postToBackstage(url string, body interface{}) (string, error) {
-	resp, err := backstageRESTClient.RESTClient.R().SetAuthToken(k.Token).SetBody(body).SetHeader("Accept", "application/json").Post(url)
+// postForResult is for the Backstage endpoints where a 400 response carries the result of validating the body, as
+// opposed to a failure of the request itself
+func (k *BackstageRESTClientWrapper) postForResult(ctx context.Context, url string, body interface{}) (int, []byte, error) {
+	resp, err := k.RESTClient.R().SetContext(ctx).SetAuthToken(k.Token).SetBody(body).SetHeader("Accept", "application/json").Post(url)
 	if err != nil {
-		return "", err
+		return 0, nil, err
 	}
-
-	return k.processUpdate(resp, "post", url, fmt.Sprintf("%#v", body))
+	rc := resp.StatusCode()
+	switch rc {
+	case 200, 201, 400:
+		klog.V(4).Infof("%s post with body %#v status code %d resp: %s\n", url, body, rc, resp.String())
+		return rc, resp.Body(), nil
+	}
+	return rc, nil, fmt.Errorf("%s post with body %#v status code %d resp: %s\n", url, body, rc, resp.String())
 }
 
-func (k *BackstageRESTClientWrapper) processFetch(resp *resty.Response, url, action string) (string, error) {
+func (k *BackstageRESTClientWrapper) processFetch(resp *resty.Response, url, action string) ([]byte, error) {
 	rc := resp.StatusCode()
-	getResp := resp.String()
 	if rc != 200 {
-		return "", fmt.Errorf("%s for %s rc %d body %s\n", action, url, rc, getResp)
+		return nil, fmt.Errorf("%s for %s rc %d body %s\n", action, url, rc, resp.String())
 	} else {
 		klog.V(4).Infof("%s for %s returned ok\n", action, url)
 	}
-	return getResp, nil
+	return resp.Body(), nil
 }
 
-func (k *BackstageRESTClientWrapper) processDelete(resp *resty.Response, url, action string) (string, error) {
+func (k *BackstageRESTClientWrapper) processDelete(resp *resty.Response, url, action string) error {
 	rc := resp.StatusCode()
-	getResp := resp.String()
 	if rc != 204 && rc != 200 {
-		return "", fmt.Errorf("%s for %s rc %d body %s\n", action, url, rc, getResp)
+		return fmt.Errorf("%s for %s rc %d body %s\n", action, url, rc, resp.String())
 	} else {
 		klog.V(4).Infof("%s for %s returned ok\n", action, url)
 	}
-	return getResp, nil
+	return nil
 }
 
-func (k *BackstageRESTClientWrapper) getFromBackstage(url string) (string, error) {
-	resp, err := backstageRESTClient.RESTClient.R().SetAuthToken(k.Token).SetHeader("Accept", "application/json").Get(url)
+func (k *BackstageRESTClientWrapper) getFromBackstage(ctx context.Context, url string) ([]byte, error) {
+	resp, err := k.RESTClient.R().SetContext(ctx).SetAuthToken(k.Token).SetHeader("Accept", "application/json").Get(url)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	return k.processFetch(resp, url, "get")
 
 }
 
-func (k *BackstageRESTClientWrapper) getWithKindParamFromBackstage(url string, qparams *nurl.Values) (string, error) {
-	req := backstageRESTClient.RESTClient.R().SetAuthToken(k.Token).SetHeader("Accept", "application/json")
-	if qparams.Has("filter") {
+func (k *BackstageRESTClientWrapper) getWithKindParamFromBackstage(ctx context.Context, url string, qparams *nurl.Values) ([]byte, error) {
+	req := k.RESTClient.R().SetContext(ctx).SetAuthToken(k.Token).SetHeader("Accept", "application/json")
+	if qparams.Has(FILTER_PARAM) || qparams.Has(CURSOR_PARAM) || qparams.Has(FACET_PARAM) {
 		req.SetQueryParamsFromValues(*qparams)
 	}
 	resp, err := req.Get(url)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	return k.processFetch(resp, url, "get")
 
 }
 
-func (k *BackstageRESTClientWrapper) deleteFromBackstage(url string) (string, error) {
-	resp, err := backstageRESTClient.RESTClient.R().SetAuthToken(k.Token).Delete(url)
+func (k *BackstageRESTClientWrapper) deleteFromBackstage(ctx context.Context, url string) error {
+	resp, err := k.RESTClient.R().SetContext(ctx).SetAuthToken(k.Token).Delete(url)
 	if err != nil {
-		return "", err
+		return err
 	}
 	return k.processDelete(resp, url, "delete")
 }
+
+func unmarshal(buf []byte, obj interface{}) error {
+	if err := json.Unmarshal(buf, obj); err != nil {
+		return fmt.Errorf("json unmarshall error for %s: %s\n", string(buf), err.Error())
+	}
+	return nil
+}
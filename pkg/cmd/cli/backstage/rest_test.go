@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/gabemontero/backstage-ai-cli/pkg/config"
 	"github.com/go-resty/resty/v2"
 	"io"
 	"net/http"
@@ -12,7 +13,9 @@ import (
 	"net/url"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 const (
@@ -20,16 +23,21 @@ const (
 	MethodPost   = "POST"
 	MethodDelete = "DELETE"
 
-	TestJSONStringOneLine  = `{"TestGet": "JSON response"}`
-	TestJSONStringIndented = `{
-    "TestGet": "JSON response"
-}`
-	TestJSONStringOneLinePlusPathParam  = `{"TestGet": "JSON response path %s"}`
 	TestJSONStringOneLinePlusQueryParam = `{"TestGet": "JSON response query %s"}`
 
 	TestPostJSONStringOneLinePlusBody = `{"TestPost": "JSON response body %s"}`
 
-	TestDeleteJSONStringOneLinePlusPathParam = `{"TestDelete": "JSON response path %s"}`
+	TestEntitiesJSON       = `[{"apiVersion":"backstage.io/v1alpha1","kind":"Component","metadata":{"name":"my-component","namespace":"default"}},{"apiVersion":"backstage.io/v1alpha1","kind":"API","metadata":{"name":"my-api","namespace":"ai"}}]`
+	TestEntityJSON         = `{"apiVersion":"backstage.io/v1alpha1","kind":"%s","metadata":{"name":"%s","namespace":"%s","uid":"uid-%[2]s"}}`
+	TestAncestryJSON       = `{"rootEntityRef":"component:default/my-model","items":[{"entity":{"kind":"Component","metadata":{"name":"my-model","namespace":"default"}},"parentEntityRefs":["location:default/generated-abc"]},{"entity":{"kind":"Location","metadata":{"name":"generated-abc","namespace":"default"},"spec":{"target":"https://my-repo/my.yaml"}},"parentEntityRefs":[]}]}`
+	TestLocationsJSON      = `[{"data":{"id":"key1","type":"url","target":"https://my-repo/key1.yaml"}},{"data":{"id":"key2","type":"url","target":"https://my-repo/key2.yaml"}}]`
+	TestLocationJSON       = `{"id":"%s","type":"url","target":"https://my-repo/%s.yaml"}`
+	TestImportLocationJSON = `{"location":{"id":"my-location-id","type":"url","target":"%s"},"entities":[{"apiVersion":"backstage.io/v1alpha1","kind":"Component","metadata":{"name":"my-component"}}]}`
+
+	TestValidateEntityErrors = `{"errors":[{"name":"InputError","message":"Policy check failed for api:default/invalid; caused by Error: <root> must have required property 'definition' - missingProperty: definition"}]}`
+	TestLocationError        = `{"error":{"name":"InputError","message":"Many errors occurred while processing location url:https://my-repo/invalid.yaml"}}`
+	TestDryRunLocationJSON   = `{"location":{"id":"dry-run-id","type":"url","target":"https://my-repo/my.yaml"},"entities":[{"apiVersion":"backstage.io/v1alpha1","kind":"Component","metadata":{"name":"my-component"}},{"apiVersion":"backstage.io/v1alpha1","kind":"API","metadata":{"name":"my-api","namespace":"ai"}}],"exists":false}`
+	TestAnalyzeLocationJSON  = `{"existingEntityFiles":[{"location":{"type":"url","target":"https://my-repo/my.yaml"},"isRegistered":true,"entity":{"apiVersion":"backstage.io/v1alpha1","kind":"Component","metadata":{"name":"my-component"}}}],"generateEntities":[]}`
 )
 
 var (
@@ -56,11 +64,11 @@ func CreateServer(t *testing.T) *httptest.Server {
 				return
 			case ENTITIES_URI:
 				w.Header().Set("Content-Type", "application/json")
-				_, _ = w.Write([]byte(TestJSONStringOneLine))
+				_, _ = w.Write([]byte(TestEntitiesJSON))
 				return
 			case LOCATION_URI:
 				w.Header().Set("Content-Type", "application/json")
-				_, _ = w.Write([]byte(TestJSONStringOneLine))
+				_, _ = w.Write([]byte(TestLocationsJSON))
 				return
 			}
 
@@ -70,15 +78,15 @@ func CreateServer(t *testing.T) *httptest.Server {
 				values := r.URL.Query()
 				filter := values.Get("filter")
 				switch {
-				case strings.Contains(filter, "api"):
+				case strings.HasPrefix(filter, "kind=api"):
 					if strings.Contains(filter, "metadata") {
 						_, _ = w.Write([]byte(apisJsonFromTags))
 					} else {
 						_, _ = w.Write([]byte(apisJson))
 					}
-				case strings.Contains(filter, "component"):
+				case strings.HasPrefix(filter, "kind=component"):
 					_, _ = w.Write([]byte(componentsJson))
-				case strings.Contains(filter, "resource"):
+				case strings.HasPrefix(filter, "kind=resource"):
 					if strings.Contains(filter, "metadata") {
 						_, _ = w.Write([]byte(resourcesFromTagsJson))
 					} else {
@@ -89,17 +97,16 @@ func CreateServer(t *testing.T) *httptest.Server {
 				}
 
 			case strings.HasPrefix(r.URL.Path, LOCATION_URI):
-				path := strings.TrimPrefix(r.URL.Path, LOCATION_URI)
+				id := strings.TrimPrefix(r.URL.Path, LOCATION_URI+"/")
 				w.Header().Set("Content-Type", "application/json")
-				if strings.Contains(path, "404") {
+				if strings.Contains(id, "404") {
 					w.WriteHeader(404)
 					return
 				}
-				_, _ = w.Write([]byte(fmt.Sprintf(TestJSONStringOneLinePlusPathParam, path)))
-			case strings.HasPrefix(r.URL.Path, API_URI):
-				path := strings.TrimPrefix(r.URL.Path, LOCATION_URI)
+				_, _ = w.Write([]byte(fmt.Sprintf(TestLocationJSON, id, id)))
+			case strings.HasSuffix(r.URL.Path, "/ancestry"):
 				w.Header().Set("Content-Type", "application/json")
-				_, _ = w.Write([]byte(fmt.Sprintf(TestJSONStringOneLinePlusPathParam, path)))
+				_, _ = w.Write([]byte(TestAncestryJSON))
 			case strings.HasPrefix(r.URL.Path, ENTITIES_URI):
 				w.Header().Set("Content-Type", "application/json")
 				segs := strings.Split(r.URL.Path, "/")
@@ -108,12 +115,59 @@ func CreateServer(t *testing.T) *httptest.Server {
 					w.WriteHeader(404)
 					return
 				}
-				_, _ = w.Write([]byte(fmt.Sprintf(TestJSONStringOneLinePlusPathParam, fmt.Sprintf("%s:%s", ns, segs[len(segs)-1]))))
+				_, _ = w.Write([]byte(fmt.Sprintf(TestEntityJSON, segs[len(segs)-3], segs[len(segs)-1], ns)))
 			}
 		case MethodPost:
 			switch r.URL.Path {
+			case BY_REFS_URI:
+				w.Header().Set("Content-Type", "application/json")
+				body := map[string][]string{}
+				bodyBuf, _ := io.ReadAll(r.Body)
+				_ = json.Unmarshal(bodyBuf, &body)
+				items := []string{}
+				for _, ref := range body["entityRefs"] {
+					kind, namespace, name, _ := ParseEntityRef(ref)
+					if namespace == "404" {
+						items = append(items, "null")
+						continue
+					}
+					items = append(items, fmt.Sprintf(TestEntityJSON, kind, name, namespace))
+				}
+				_, _ = w.Write([]byte(`{"items":[` + strings.Join(items, ",") + `]}`))
+				return
+			case VALIDATE_URI:
+				w.Header().Set("Content-Type", "application/json")
+				bodyBuf, _ := io.ReadAll(r.Body)
+				if strings.Contains(string(bodyBuf), "invalid") {
+					w.WriteHeader(400)
+					_, _ = w.Write([]byte(TestValidateEntityErrors))
+					return
+				}
+				_, _ = w.Write([]byte("{}"))
+				return
+			case ANALYZE_URI:
+				w.Header().Set("Content-Type", "application/json")
+				bodyBuf, _ := io.ReadAll(r.Body)
+				if strings.Contains(string(bodyBuf), "invalid") {
+					w.WriteHeader(400)
+					_, _ = w.Write([]byte(TestLocationError))
+					return
+				}
+				_, _ = w.Write([]byte(TestAnalyzeLocationJSON))
+				return
 			case LOCATION_URI:
 				w.Header().Set("Content-Type", "application/json")
+				if r.URL.Query().Get(DRY_RUN_PARAM) == "true" {
+					bodyBuf, _ := io.ReadAll(r.Body)
+					if strings.Contains(string(bodyBuf), "invalid") {
+						w.WriteHeader(400)
+						_, _ = w.Write([]byte(TestLocationError))
+						return
+					}
+					w.WriteHeader(201)
+					_, _ = w.Write([]byte(TestDryRunLocationJSON))
+					return
+				}
 				bodyBuf, err := io.ReadAll(r.Body)
 				if err != nil {
 					_, _ = w.Write([]byte(fmt.Sprintf(TestPostJSONStringOneLinePlusBody, err.Error())))
@@ -136,18 +190,24 @@ func CreateServer(t *testing.T) *httptest.Server {
 					w.WriteHeader(500)
 					return
 				}
-				_, _ = w.Write([]byte(fmt.Sprintf(TestPostJSONStringOneLinePlusBody, data.Target)))
+				w.WriteHeader(201)
+				_, _ = w.Write([]byte(fmt.Sprintf(TestImportLocationJSON, data.Target)))
 			}
 		case MethodDelete:
 			switch {
+			case strings.HasPrefix(r.URL.Path, ENTITIES_URI+"/by-uid/"):
+				if strings.Contains(r.URL.Path, "404") {
+					w.WriteHeader(404)
+					return
+				}
+				w.WriteHeader(204)
 			case strings.HasPrefix(r.URL.Path, LOCATION_URI):
 				path := strings.TrimPrefix(r.URL.Path, LOCATION_URI)
 				if strings.Contains(path, "404") {
 					w.WriteHeader(404)
 					return
 				}
-				w.Header().Set("Content-Type", "application/json")
-				_, _ = w.Write([]byte(fmt.Sprintf(TestDeleteJSONStringOneLinePlusPathParam, path)))
+				w.WriteHeader(204)
 			}
 		}
 	})
@@ -206,6 +266,13 @@ func AssertLineCompare(t *testing.T, str, expect string, minLine int) {
 	}
 }
 
+// toJSON renders typed client results the way the CLI prints them
+func toJSON(t *testing.T, obj interface{}) string {
+	buf, err := json.MarshalIndent(obj, "", "    ")
+	AssertError(t, err)
+	return string(buf)
+}
+
 func Equal(expected, got interface{}) bool {
 	return reflect.DeepEqual(expected, got)
 }
@@ -251,3 +318,46 @@ func LogResponse(t *testing.T, resp *resty.Response) {
 	t.Logf("Response Cookies: %v", resp.Cookies())
 	t.Logf("Response Body: %v", resp)
 }
+
+func TestSetupBackstageRESTClientPerConfig(t *testing.T) {
+	servers := []*httptest.Server{}
+	clients := []CatalogClient{}
+	for i := 0; i < 2; i++ {
+		body := fmt.Sprintf(`[{"data":{"id":"instance-%d","type":"url","target":"https://my-repo/my.yaml"}}]`, i)
+		ts := CreateTestServer(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(body))
+		})
+		defer ts.Close()
+		servers = append(servers, ts)
+		clients = append(clients, SetupBackstageRESTClient(&config.Config{BackstageURL: ts.URL, BackstageToken: fmt.Sprintf("token-%d", i)}))
+	}
+
+	// the clients are used concurrently, and each one keeps talking to its own Backstage instance
+	wg := sync.WaitGroup{}
+	for i, client := range clients {
+		for j := 0; j < 5; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				locations, err := client.ListLocations(context.Background())
+				AssertError(t, err)
+				AssertEqual(t, fmt.Sprintf("instance-%d", i), locations[0].ID)
+			}()
+		}
+	}
+	wg.Wait()
+}
+
+func TestBackstageRESTClientContext(t *testing.T) {
+	ts := CreateTestServer(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := SetupBackstageTestRESTClient(ts).GetLocation(ctx, "key1")
+	if err == nil || !strings.Contains(err.Error(), "deadline exceeded") {
+		t.Errorf("expected the request to be canceled but got %v", err)
+	}
+}
@@ -0,0 +1,210 @@
+package backstage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/gabemontero/backstage-ai-cli/pkg/config"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	CATALOG_INFO_FILE = "catalog-info.yaml"
+	MKDOCS_FILE       = "mkdocs.yml"
+	DOCS_DIR          = "docs"
+	INDEX_FILE        = "index.md"
+)
+
+// TechDocsPopulator is implemented by populators that have model metadata, beyond what goes into the entity itself,
+// for seeding the TechDocs of the entity.
+type TechDocsPopulator interface {
+	GetModelFormats() []string
+	GetCustomProperties() map[string]string
+}
+
+// HealthPopulator is implemented by populators that know whether the model is being served, for the health section of
+// the TechDocs
+type HealthPopulator interface {
+	GetHealth() []HealthCheck
+}
+
+// HealthCheck is a line of the health section, such as a condition of an InferenceService
+type HealthCheck struct {
+	Name    string
+	Status  string
+	Message string
+}
+
+// ModelOutput is where the entities for a model are printed.  Without an output directory that is simply the
+// provided writer.  With one, it is the catalog-info.yaml file in a directory for the model, where the TechDocs
+// referenced by the entities can also be written.
+type ModelOutput struct {
+	io.Writer
+	dir  string
+	file *os.File
+	// definitions are the API definitions printed, by entity name, so the TechDocs show them without fetching them again
+	definitions map[string]string
+}
+
+func NewModelOutput(out io.Writer, outputDir, modelName string) (*ModelOutput, error) {
+	if len(outputDir) == 0 {
+		return &ModelOutput{Writer: out}, nil
+	}
+	dir := filepath.Join(outputDir, modelName)
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Create(filepath.Join(dir, CATALOG_INFO_FILE))
+	if err != nil {
+		return nil, err
+	}
+	return &ModelOutput{Writer: file, dir: dir, file: file}, nil
+}
+
+func (o *ModelOutput) Dir() string {
+	return o.dir
+}
+
+func (o *ModelOutput) setDefinition(name, definition string) {
+	if o.definitions == nil {
+		o.definitions = map[string]string{}
+	}
+	o.definitions[name] = definition
+}
+
+func (o *ModelOutput) Close() error {
+	if o.file == nil {
+		return nil
+	}
+	return o.file.Close()
+}
+
+// WriteTechDocs creates the mkdocs.yml and docs/index.md for each of the techdocs-ref directories of the populators,
+// seeding the index with the model metadata from the populators that share the directory.  The directory of the
+// model server Component, which is expected first, also gets usage, API, and license pages, fetching the model
+// documentation with the REST settings of the config.  It does nothing when there is no output directory.
+func (o *ModelOutput) WriteTechDocs(ctx context.Context, cfg *config.Config, pops ...CommonPopulator) error {
+	if len(o.dir) == 0 {
+		return nil
+	}
+	refs := []string{}
+	popsByRef := map[string][]CommonPopulator{}
+	for _, pop := range pops {
+		ref := pop.GetTechdocRef()
+		if _, ok := popsByRef[ref]; !ok {
+			refs = append(refs, ref)
+		}
+		popsByRef[ref] = append(popsByRef[ref], pop)
+	}
+
+	for _, ref := range refs {
+		refPops := popsByRef[ref]
+		dir := filepath.Join(o.dir, ref)
+		err := os.MkdirAll(filepath.Join(dir, DOCS_DIR), 0755)
+		if err != nil {
+			return err
+		}
+		pages := [][2]string{{"Home", INDEX_FILE}}
+		if _, ok := refPops[0].(ComponentPopulator); ok && ref == pops[0].GetTechdocRef() {
+			pages, err = writeModelServerPages(ctx, cfg, filepath.Join(dir, DOCS_DIR), refPops[0], pops, o.definitions)
+		} else {
+			index := &bytes.Buffer{}
+			for _, pop := range refPops {
+				writeIndex(index, pop)
+			}
+			err = os.WriteFile(filepath.Join(dir, DOCS_DIR, INDEX_FILE), index.Bytes(), 0644)
+		}
+		if err != nil {
+			return err
+		}
+		err = os.WriteFile(filepath.Join(dir, MKDOCS_FILE), []byte(buildMkdocs(refPops[0].GetDisplayName(), pages)), 0644)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func buildMkdocs(siteName string, pages [][2]string) string {
+	buffer := &bytes.Buffer{}
+	fmt.Fprintf(buffer, "site_name: %q\nnav:\n", siteName)
+	for _, page := range pages {
+		fmt.Fprintf(buffer, "  - %s: %s\n", page[0], page[1])
+	}
+	buffer.WriteString("plugins:\n  - techdocs-core\n")
+	return buffer.String()
+}
+
+func writeIndex(buffer *bytes.Buffer, pop CommonPopulator) {
+	fmt.Fprintf(buffer, "# %s\n\n", pop.GetDisplayName())
+	if description := pop.GetDescription(); len(description) > 0 {
+		fmt.Fprintf(buffer, "%s\n\n", description)
+	}
+
+	var formats []string
+	var properties map[string]string
+	if docPop, ok := pop.(TechDocsPopulator); ok {
+		formats = docPop.GetModelFormats()
+		properties = docPop.GetCustomProperties()
+	}
+
+	if len(formats) > 0 {
+		buffer.WriteString("## Model formats\n\n")
+		for _, format := range formats {
+			fmt.Fprintf(buffer, "- %s\n", format)
+		}
+		buffer.WriteString("\n")
+	}
+
+	if links := pop.GetLinks(); len(links) > 0 {
+		buffer.WriteString("## URLs\n\n")
+		for _, link := range links {
+			title := link.Title
+			if len(title) == 0 {
+				title = link.URL
+			}
+			fmt.Fprintf(buffer, "- [%s](%s)\n", title, link.URL)
+		}
+		buffer.WriteString("\n")
+	}
+
+	if healthPop, ok := pop.(HealthPopulator); ok {
+		if checks := healthPop.GetHealth(); len(checks) > 0 {
+			buffer.WriteString("## Health\n\n")
+			buffer.WriteString("| Check | Status | Details |\n")
+			buffer.WriteString("|-------|--------|---------|\n")
+			for _, check := range checks {
+				fmt.Fprintf(buffer, "| %s | %s | %s |\n", check.Name, check.Status, strings.ReplaceAll(check.Message, "|", "\\|"))
+			}
+			buffer.WriteString("\n")
+		}
+	}
+
+	if tags := pop.GetTags(); len(tags) > 0 {
+		buffer.WriteString("## Tags\n\n")
+		for _, tag := range tags {
+			fmt.Fprintf(buffer, "- %s\n", tag)
+		}
+		buffer.WriteString("\n")
+	}
+
+	if len(properties) > 0 {
+		buffer.WriteString("## Custom properties\n\n")
+		buffer.WriteString("| Property | Value |\n")
+		buffer.WriteString("|----------|-------|\n")
+		keys := make([]string, 0, len(properties))
+		for key := range properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(buffer, "| %s | %s |\n", key, strings.ReplaceAll(properties[key], "|", "\\|"))
+		}
+		buffer.WriteString("\n")
+	}
+}
@@ -0,0 +1,226 @@
+package backstage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/gabemontero/backstage-ai-cli/pkg/config"
+	"github.com/gabemontero/backstage-ai-cli/pkg/util"
+	"github.com/go-resty/resty/v2"
+	"k8s.io/klog/v2"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sigs.k8s.io/yaml"
+	"strings"
+)
+
+const (
+	USAGE_FILE   = "usage.md"
+	API_FILE     = "api.md"
+	LICENSE_FILE = "license.md"
+
+	HUGGINGFACE_STORAGE_PREFIX = "hf://"
+	HUGGINGFACE_MODEL_CARD_URL = "https://huggingface.co/%s/raw/main/README.md"
+
+	LICENSE_PROPERTY = "license"
+)
+
+// ModelDocsPopulator is implemented by populators that can locate documentation for the model, such as a Hugging
+// Face model card or a Kubeflow DocArtifact, for generating the TechDocs pages of the model server.
+type ModelDocsPopulator interface {
+	// GetModelDocURLs returns the URLs of markdown documentation for the model
+	GetModelDocURLs() []string
+	// GetServedModelName returns the name the inference endpoints serve the model under
+	GetServedModelName() string
+}
+
+// HuggingFaceModelCardURL returns the URL of the model card for a Hugging Face repository like 'ibm-granite/granite-3.0-8b-instruct'
+// or storage URI like 'hf://ibm-granite/granite-3.0-8b-instruct'
+func HuggingFaceModelCardURL(repo string) string {
+	return fmt.Sprintf(HUGGINGFACE_MODEL_CARD_URL, strings.Trim(strings.TrimPrefix(repo, HUGGINGFACE_STORAGE_PREFIX), "/"))
+}
+
+// IsInferenceLink determines whether a link of an entity is one of the REST inference endpoints of the model
+func IsInferenceLink(link EntityLink) bool {
+	return link.Title == LINK_API_URL || strings.HasSuffix(link.Title, LINK_REST_MODEL_SERVING_URL)
+}
+
+type modelDoc struct {
+	url         string
+	frontMatter map[string]interface{}
+	body        string
+}
+
+// fetchModelDocs pulls the markdown for the model, separating out any YAML front matter like the metadata block at
+// the top of a Hugging Face model card.  The documentation is typically on the public internet, so unlike the
+// inference endpoints in the cluster, the certificates are verified.
+func fetchModelDocs(ctx context.Context, client *resty.Client, pop CommonPopulator) []modelDoc {
+	docs := []modelDoc{}
+	docPop, ok := pop.(ModelDocsPopulator)
+	if !ok {
+		return docs
+	}
+	for _, url := range docPop.GetModelDocURLs() {
+		resp, err := client.R().SetContext(ctx).Get(url)
+		if err == nil && resp.StatusCode() != http.StatusOK {
+			err = fmt.Errorf("rc %d", resp.StatusCode())
+		}
+		if err != nil {
+			klog.Warningf("unable to fetch model documentation from %s: %s", url, err.Error())
+			continue
+		}
+		docs = append(docs, parseModelDoc(url, resp.String()))
+	}
+	return docs
+}
+
+func parseModelDoc(url, content string) modelDoc {
+	doc := modelDoc{url: url, body: content, frontMatter: map[string]interface{}{}}
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+	if !strings.HasPrefix(normalized, "---\n") {
+		return doc
+	}
+	end := strings.Index(normalized[4:], "\n---")
+	if end < 0 {
+		return doc
+	}
+	if err := yaml.Unmarshal([]byte(normalized[4:4+end]), &doc.frontMatter); err != nil {
+		klog.V(4).Infof("front matter of %s is not valid yaml: %s", url, err.Error())
+		return doc
+	}
+	doc.body = strings.TrimLeft(normalized[4+end+len("\n---"):], "\n")
+	return doc
+}
+
+// writeModelServerPages generates the overview, usage, API, and license pages for the TechDocs of the model server,
+// returning the mkdocs nav entries for them
+func writeModelServerPages(ctx context.Context, cfg *config.Config, docsDir string, comp CommonPopulator, pops []CommonPopulator, definitions map[string]string) ([][2]string, error) {
+	docs := fetchModelDocs(ctx, util.ConfigureRESTClient(resty.New(), cfg), comp)
+
+	overview := &bytes.Buffer{}
+	writeIndex(overview, comp)
+	for _, doc := range docs {
+		fmt.Fprintf(overview, "## Model documentation\n\nFrom [%s](%s)\n\n%s\n", doc.url, doc.url, demoteHeadings(doc.body))
+	}
+
+	pages := [][2]string{
+		{"Overview", INDEX_FILE},
+		{"Usage", USAGE_FILE},
+		{"API", API_FILE},
+		{"License", LICENSE_FILE},
+	}
+	contents := map[string][]byte{
+		INDEX_FILE:   overview.Bytes(),
+		USAGE_FILE:   buildUsage(comp),
+		API_FILE:     buildAPIPage(comp, pops, definitions),
+		LICENSE_FILE: buildLicense(comp, docs),
+	}
+	for _, page := range pages {
+		err := os.WriteFile(filepath.Join(docsDir, page[1]), contents[page[1]], 0644)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return pages, nil
+}
+
+func buildUsage(pop CommonPopulator) []byte {
+	buffer := &bytes.Buffer{}
+	buffer.WriteString("# Usage\n\n")
+	modelName := pop.GetName()
+	if docPop, ok := pop.(ModelDocsPopulator); ok && len(docPop.GetServedModelName()) > 0 {
+		modelName = docPop.GetServedModelName()
+	}
+
+	found := false
+	for _, link := range pop.GetLinks() {
+		if !IsInferenceLink(link) {
+			continue
+		}
+		found = true
+		url := strings.TrimSuffix(link.URL, "/")
+		v1 := fmt.Sprintf("%s/v1/models/%s:predict", url, modelName)
+		v2 := fmt.Sprintf("%s/v2/models/%s/infer", url, modelName)
+		fmt.Fprintf(buffer, "## %s\n\n", link.Title)
+		fmt.Fprintf(buffer, "With the V1 inference protocol:\n\n")
+		fmt.Fprintf(buffer, "```shell\ncurl -X POST %s \\\n  -H \"Content-Type: application/json\" \\\n  -H \"Authorization: Bearer $TOKEN\" \\\n  -d '{\"instances\": [[1.0, 2.0, 3.0]]}'\n```\n\n", v1)
+		fmt.Fprintf(buffer, "```python\nimport os\nimport requests\n\nresp = requests.post(\n    \"%s\",\n    headers={\"Authorization\": f\"Bearer {os.environ['TOKEN']}\"},\n    json={\"instances\": [[1.0, 2.0, 3.0]]},\n)\nprint(resp.json())\n```\n\n", v1)
+		fmt.Fprintf(buffer, "With the V2 (Open Inference) protocol:\n\n")
+		fmt.Fprintf(buffer, "```shell\ncurl -X POST %s \\\n  -H \"Content-Type: application/json\" \\\n  -H \"Authorization: Bearer $TOKEN\" \\\n  -d '{\"inputs\": [{\"name\": \"input-0\", \"shape\": [1, 3], \"datatype\": \"FP32\", \"data\": [1.0, 2.0, 3.0]}]}'\n```\n\n", v2)
+		fmt.Fprintf(buffer, "```python\nimport os\nimport requests\n\nresp = requests.post(\n    \"%s\",\n    headers={\"Authorization\": f\"Bearer {os.environ['TOKEN']}\"},\n    json={\"inputs\": [{\"name\": \"input-0\", \"shape\": [1, 3], \"datatype\": \"FP32\", \"data\": [1.0, 2.0, 3.0]}]},\n)\nprint(resp.json())\n```\n\n", v2)
+	}
+	if !found {
+		buffer.WriteString("No inference endpoints are known for this model yet.\n")
+	}
+	return buffer.Bytes()
+}
+
+// buildAPIPage shows the API definitions already printed with the entities, only asking the populators for the
+// definitions that were not
+func buildAPIPage(comp CommonPopulator, pops []CommonPopulator, definitions map[string]string) []byte {
+	buffer := &bytes.Buffer{}
+	buffer.WriteString("# API\n\n")
+	for _, link := range comp.GetLinks() {
+		if !IsInferenceLink(link) {
+			fmt.Fprintf(buffer, "- [%s](%s)\n", link.Title, link.URL)
+		}
+	}
+	buffer.WriteString("\n")
+	for _, pop := range pops {
+		apiPop, ok := pop.(APIPopulator)
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(buffer, "## %s\n\n", apiPop.GetDisplayName())
+		definition, ok := definitions[apiPop.GetName()]
+		if !ok {
+			definition = apiPop.GetDefinition()
+		}
+		if definition == API_DEFINITION_PLACEHOLDER || len(definition) == 0 {
+			buffer.WriteString("No API definition is available yet.\n\n")
+			continue
+		}
+		fmt.Fprintf(buffer, "```json\n%s\n```\n\n", definition)
+	}
+	return buffer.Bytes()
+}
+
+func buildLicense(pop CommonPopulator, docs []modelDoc) []byte {
+	buffer := &bytes.Buffer{}
+	buffer.WriteString("# License\n\n")
+	licenses := []string{}
+	for _, doc := range docs {
+		if license, ok := doc.frontMatter[LICENSE_PROPERTY]; ok {
+			licenses = append(licenses, fmt.Sprintf("%v (from [%s](%s))", license, doc.url, doc.url))
+		}
+	}
+	if docPop, ok := pop.(TechDocsPopulator); ok {
+		if license, ok := docPop.GetCustomProperties()[LICENSE_PROPERTY]; ok {
+			licenses = append(licenses, license)
+		}
+	}
+	if len(licenses) == 0 {
+		buffer.WriteString("No license information is available for this model.\n")
+		return buffer.Bytes()
+	}
+	for _, license := range licenses {
+		fmt.Fprintf(buffer, "- %s\n", license)
+	}
+	return buffer.Bytes()
+}
+
+// demoteHeadings keeps the headings of fetched documentation below the headings of the generated page
+func demoteHeadings(markdown string) string {
+	lines := strings.Split(markdown, "\n")
+	inCode := false
+	for i, line := range lines {
+		if strings.HasPrefix(line, "```") {
+			inCode = !inCode
+		}
+		if !inCode && strings.HasPrefix(line, "#") {
+			lines[i] = "##" + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,126 @@
+package backstage
+
+import (
+	"bytes"
+	"context"
+	"github.com/gabemontero/backstage-ai-cli/pkg/config"
+	"github.com/gabemontero/backstage-ai-cli/pkg/util"
+	"github.com/go-resty/resty/v2"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testModelCard = `---
+license: apache-2.0
+language:
+- en
+---
+
+# Granite
+
+A model for testing.
+
+` + "```python\n# not a heading\n```\n"
+
+type testDocsPopulator struct {
+	testPopulator
+	urls []string
+}
+
+func (pop *testDocsPopulator) GetModelDocURLs() []string  { return pop.urls }
+func (pop *testDocsPopulator) GetServedModelName() string { return "granite" }
+
+func TestHuggingFaceModelCardURL(t *testing.T) {
+	for _, repo := range []string{"hf://ibm-granite/granite-3.0", "ibm-granite/granite-3.0", "hf://ibm-granite/granite-3.0/"} {
+		AssertEqual(t, "https://huggingface.co/ibm-granite/granite-3.0/raw/main/README.md", HuggingFaceModelCardURL(repo))
+	}
+}
+
+func TestParseModelDoc(t *testing.T) {
+	doc := parseModelDoc("https://my-model-card", testModelCard)
+	AssertEqual(t, "apache-2.0", doc.frontMatter[LICENSE_PROPERTY])
+	AssertContains(t, doc.body, "# Granite")
+
+	doc = parseModelDoc("https://my-model-card", "# No front matter")
+	AssertEqual(t, 0, len(doc.frontMatter))
+	AssertEqual(t, "# No front matter", doc.body)
+}
+
+func TestWriteModelServerPages(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/README.md" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(testModelCard))
+	}))
+	defer ts.Close()
+
+	outputDir := t.TempDir()
+	out, err := NewModelOutput(&bytes.Buffer{}, outputDir, "my-model")
+	AssertError(t, err)
+	comp := &testDocsPopulator{
+		testPopulator: testPopulator{name: "my-model", ref: "./"},
+		urls:          []string{ts.URL + "/README.md", ts.URL + "/missing.md"},
+	}
+	api := &testPopulator{name: "my-api", ref: "api/"}
+	AssertError(t, out.WriteTechDocs(context.Background(), &config.Config{}, comp, api))
+	AssertError(t, out.Close())
+
+	dir := filepath.Join(outputDir, "my-model")
+	read := func(file string) string {
+		buf, err := os.ReadFile(filepath.Join(dir, file))
+		AssertError(t, err)
+		return string(buf)
+	}
+
+	mkdocs := read(MKDOCS_FILE)
+	for _, page := range []string{"Overview: index.md", "Usage: usage.md", "API: api.md", "License: license.md"} {
+		AssertContains(t, mkdocs, page)
+	}
+	AssertContains(t, read(filepath.Join("api", MKDOCS_FILE)), "Home: index.md")
+
+	index := read(filepath.Join(DOCS_DIR, INDEX_FILE))
+	AssertContains(t, index, "# The my-model model")
+	AssertContains(t, index, "## Model documentation")
+	AssertContains(t, index, "### Granite")
+	AssertContains(t, index, "# not a heading")
+
+	usage := read(filepath.Join(DOCS_DIR, USAGE_FILE))
+	AssertContains(t, usage, "curl -X POST https://my-model.com/v1/models/granite:predict")
+	AssertContains(t, usage, "https://my-model.com/v2/models/granite/infer")
+	AssertContains(t, usage, "import requests")
+
+	AssertContains(t, read(filepath.Join(DOCS_DIR, API_FILE)), "## The my-api model")
+	AssertContains(t, read(filepath.Join(DOCS_DIR, LICENSE_FILE)), "- apache-2.0 (from")
+}
+
+func TestFetchModelDocs(t *testing.T) {
+	calls := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(testModelCard))
+	})
+	client := util.ConfigureRESTClient(resty.New(), &config.Config{Retries: 1, RetryWait: time.Millisecond, RetryMaxWait: time.Millisecond})
+
+	// failures are retried with the settings of the config
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+	docs := fetchModelDocs(context.Background(), client, &testDocsPopulator{urls: []string{ts.URL + "/README.md"}})
+	AssertEqual(t, 1, len(docs))
+	AssertEqual(t, 2, calls)
+
+	// and the certificate of the server is verified
+	tls := httptest.NewTLSServer(handler)
+	defer tls.Close()
+	docs = fetchModelDocs(context.Background(), client, &testDocsPopulator{urls: []string{tls.URL + "/README.md"}})
+	AssertEqual(t, 0, len(docs))
+}
@@ -0,0 +1,115 @@
+package backstage
+
+import (
+	"bytes"
+	"context"
+	"github.com/gabemontero/backstage-ai-cli/pkg/config"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type testPopulator struct {
+	name string
+	ref  string
+}
+
+func (pop *testPopulator) GetOwner() string          { return "owner" }
+func (pop *testPopulator) GetLifecycle() string      { return "production" }
+func (pop *testPopulator) GetName() string           { return pop.name }
+func (pop *testPopulator) GetDescription() string    { return "a test model" }
+func (pop *testPopulator) GetTags() []string         { return []string{"genai"} }
+func (pop *testPopulator) GetProvidedAPIs() []string { return []string{} }
+func (pop *testPopulator) GetTechdocRef() string     { return pop.ref }
+func (pop *testPopulator) GetDisplayName() string    { return "The " + pop.name + " model" }
+func (pop *testPopulator) GetModelFormats() []string { return []string{"onnx 1"} }
+func (pop *testPopulator) GetDependsOn() []string    { return []string{} }
+func (pop *testPopulator) GetDependencyOf() []string { return []string{} }
+func (pop *testPopulator) GetDefinition() string     { return API_DEFINITION_PLACEHOLDER }
+func (pop *testPopulator) GetCustomProperties() map[string]string {
+	return map[string]string{"accuracy": "0.9", "dataset": "a|b"}
+}
+func (pop *testPopulator) GetLinks() []EntityLink {
+	return []EntityLink{{URL: "https://my-model.com", Title: LINK_API_URL}}
+}
+
+func TestModelOutputNoDir(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	out, err := NewModelOutput(buffer, "", "my-model")
+	AssertError(t, err)
+	AssertError(t, PrintComponent(&testPopulator{name: "my-model", ref: "./"}, out))
+	AssertError(t, out.WriteTechDocs(context.Background(), &config.Config{}, &testPopulator{name: "my-model", ref: "./"}))
+	AssertError(t, out.Close())
+	AssertContains(t, buffer.String(), "backstage.io/techdocs-ref: dir:./")
+	AssertEqual(t, "", out.Dir())
+}
+
+func TestModelOutputWriteTechDocs(t *testing.T) {
+	outputDir := t.TempDir()
+	out, err := NewModelOutput(&bytes.Buffer{}, outputDir, "my-model")
+	AssertError(t, err)
+
+	comp := &testPopulator{name: "my-model", ref: "./"}
+	res1 := &testPopulator{name: "v1", ref: "resource/"}
+	res2 := &testPopulator{name: "v2", ref: "resource/"}
+	api := &testPopulator{name: "my-api", ref: "api/"}
+	AssertError(t, PrintComponent(comp, out))
+	AssertError(t, PrintResource(res1, out))
+	AssertError(t, PrintResource(res2, out))
+	AssertError(t, PrintAPI(api, out))
+	AssertError(t, out.WriteTechDocs(context.Background(), &config.Config{}, comp, res1, res2, api))
+	AssertError(t, out.Close())
+
+	dir := filepath.Join(outputDir, "my-model")
+	buf, err := os.ReadFile(filepath.Join(dir, CATALOG_INFO_FILE))
+	AssertError(t, err)
+	AssertContains(t, string(buf), "kind: Component")
+	AssertContains(t, string(buf), "kind: API")
+
+	for _, ref := range []string{"./", "resource/", "api/"} {
+		buf, err = os.ReadFile(filepath.Join(dir, ref, MKDOCS_FILE))
+		AssertError(t, err)
+		AssertContains(t, string(buf), "techdocs-core")
+		_, err = os.Stat(filepath.Join(dir, ref, DOCS_DIR, INDEX_FILE))
+		AssertError(t, err)
+	}
+
+	buf, err = os.ReadFile(filepath.Join(dir, "resource", DOCS_DIR, INDEX_FILE))
+	AssertError(t, err)
+	index := string(buf)
+	AssertContains(t, index, "# The v1 model")
+	AssertContains(t, index, "# The v2 model")
+	AssertContains(t, index, "- onnx 1")
+	AssertContains(t, index, "- [API URL](https://my-model.com)")
+	AssertContains(t, index, "| accuracy | 0.9 |")
+	AssertContains(t, index, `| dataset | a\|b |`)
+}
+
+// fetchingPopulator counts the calls to GetDefinition, which fetch the definition over HTTP for the real populators
+type fetchingPopulator struct {
+	testPopulator
+	fetches int
+}
+
+func (pop *fetchingPopulator) GetDefinition() string {
+	pop.fetches++
+	return `{"openapi": "3.0.0"}`
+}
+
+func TestModelOutputAPIDefinitionFetchedOnce(t *testing.T) {
+	outputDir := t.TempDir()
+	out, err := NewModelOutput(&bytes.Buffer{}, outputDir, "my-model")
+	AssertError(t, err)
+
+	comp := &testPopulator{name: "my-model", ref: "./"}
+	api := &fetchingPopulator{testPopulator: testPopulator{name: "my-api", ref: "api/"}}
+	AssertError(t, PrintComponent(comp, out))
+	AssertError(t, PrintAPI(api, out))
+	AssertError(t, out.WriteTechDocs(context.Background(), &config.Config{}, comp, api))
+	AssertError(t, out.Close())
+
+	AssertEqual(t, 1, api.fetches)
+	buf, err := os.ReadFile(filepath.Join(outputDir, "my-model", DOCS_DIR, API_FILE))
+	AssertError(t, err)
+	AssertContains(t, string(buf), `{"openapi": "3.0.0"}`)
+}
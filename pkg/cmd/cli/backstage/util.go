@@ -2,23 +2,60 @@ package backstage
 
 import (
 	"net/url"
+	"regexp"
 	"sort"
 	"strings"
 )
 
-func buildKeys(args ...string) map[string][]string {
-	keys := map[string][]string{}
+// MAX_TAG_LENGTH is the longest tag, or entity name, Backstage accepts
+const MAX_TAG_LENGTH = 63
+
+var (
+	invalidTagChars  = regexp.MustCompile(`[^a-z0-9:+#]+`)
+	invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+)
+
+// SanitizeTag lower cases the value and replaces the characters Backstage does not allow in a tag with '-', returning
+// an empty string when nothing is left
+func SanitizeTag(value string) string {
+	tag := strings.Trim(invalidTagChars.ReplaceAllString(strings.ToLower(value), "-"), "-")
+	if len(tag) > MAX_TAG_LENGTH {
+		tag = strings.TrimRight(tag[:MAX_TAG_LENGTH], "-")
+	}
+	return tag
+}
+
+// SanitizeName makes the value a valid entity name or label value, sequences of letters and digits separated by one
+// of '-', '_', or '.', replacing any other characters with '-'
+func SanitizeName(value string) string {
+	name := invalidNameChars.ReplaceAllStringFunc(value, func(separator string) string {
+		if separator == "-" || separator == "_" || separator == "." {
+			return separator
+		}
+		return "-"
+	})
+	name = strings.Trim(name, "-_.")
+	if len(name) > MAX_TAG_LENGTH {
+		name = strings.TrimRight(name[:MAX_TAG_LENGTH], "-_.")
+	}
+	return name
+}
+
+type entityKey struct {
+	namespace string
+	name      string
+}
+
+// buildKeys splits the 'namespace:name' args, where the namespace is optional, in the order they were provided
+func buildKeys(args ...string) []entityKey {
+	keys := []entityKey{}
 	for _, arg := range args {
 		array := strings.Split(arg, ":")
 		if len(array) == 1 {
-			arr := keys[DEFAULT_NS]
-			arr = append(arr, arg)
-			keys[DEFAULT_NS] = arr
+			keys = append(keys, entityKey{namespace: DEFAULT_NS, name: arg})
 			continue
 		}
-		arr := keys[array[0]]
-		arr = append(arr, array[1])
-		keys[array[0]] = arr
+		keys = append(keys, entityKey{namespace: array[0], name: array[1]})
 	}
 	return keys
 }
@@ -48,12 +85,16 @@ func tagsMatch(args, tags []string) bool {
 	return true
 }
 
-func updateQParams(subset bool, filterValue string, args []string, qparams *url.Values) *url.Values {
+func updateQParams(subset bool, filterValues []string, args []string, qparams *url.Values) *url.Values {
 	if subset {
-		for _, arg := range args {
-			filterValue = filterValue + ",metadata.tags=" + arg
+		filters := []string{}
+		for _, filterValue := range filterValues {
+			for _, arg := range args {
+				filterValue = filterValue + ",metadata.tags=" + arg
+			}
+			filters = append(filters, filterValue)
 		}
-		qparams.Set("filter", filterValue)
+		(*qparams)["filter"] = filters
 	} else {
 		//TODO could not determine single query parameter format that resulted in returning
 		// a list of entities whose `metadata.tags` array directly matched a provided list of args;
@@ -0,0 +1,31 @@
+package backstage
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizeTag(t *testing.T) {
+	for value, tag := range map[string]string{
+		"sklearn":                "sklearn",
+		"runtime:My_Runtime":     "runtime:my-runtime",
+		"f1-v1.0":                "f1-v1-0",
+		"--grpc v2--":            "grpc-v2",
+		"._/":                    "",
+		strings.Repeat("a-", 40): strings.TrimRight(strings.Repeat("a-", 32), "-"),
+	} {
+		AssertEqual(t, tag, SanitizeTag(value))
+	}
+}
+
+func TestSanitizeName(t *testing.T) {
+	for value, name := range map[string]string{
+		"model-1_v1.0":            "model-1_v1.0",
+		"My Model / v1":           "My-Model-v1",
+		"--v1--":                  "v1",
+		"a..b":                    "a-b",
+		strings.Repeat("ab.", 30): strings.TrimRight(strings.Repeat("ab.", 21), "."),
+	} {
+		AssertEqual(t, name, SanitizeName(value))
+	}
+}
@@ -0,0 +1,264 @@
+package backstage
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	ENTITY_SCHEMA      = "Entity"
+	ENTITY_META_SCHEMA = "EntityMeta"
+	NAME_MAX_LENGTH    = 63
+)
+
+// schemaFS holds trimmed copies of the Backstage catalog-model JSON schemas
+// https://github.com/backstage/backstage/tree/master/packages/catalog-model/src/schema
+//
+//go:embed schema/*.json
+var schemaFS embed.FS
+
+var (
+	// namePattern and namespacePattern mirror the checks done by the Backstage FieldFormatEntityPolicy
+	namePattern      = regexp.MustCompile(`^([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]$`)
+	namespacePattern = regexp.MustCompile(`^[a-z0-9]+(?:-+[a-z0-9]+)*$`)
+
+	kindSchemas = map[string]string{
+		KindComponent: "ComponentV1alpha1",
+		KindResource:  "ResourceV1alpha1",
+		KindAPI:       "ApiV1alpha1",
+		KindSystem:    "SystemV1alpha1",
+	}
+
+	schemas = loadSchemas()
+)
+
+// jsonSchema captures the subset of JSON schema draft-07 used by the Backstage catalog-model schemas
+type jsonSchema struct {
+	ID                   string                 `json:"$id"`
+	Ref                  string                 `json:"$ref"`
+	Type                 string                 `json:"type"`
+	Required             []string               `json:"required"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	AdditionalProperties json.RawMessage        `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	AllOf                []*jsonSchema          `json:"allOf"`
+	Enum                 []interface{}          `json:"enum"`
+	MinLength            *int                   `json:"minLength"`
+}
+
+func loadSchemas() map[string]*jsonSchema {
+	ret := map[string]*jsonSchema{}
+	entries, err := schemaFS.ReadDir("schema")
+	if err != nil {
+		panic(err)
+	}
+	for _, entry := range entries {
+		buf, err := schemaFS.ReadFile("schema/" + entry.Name())
+		if err != nil {
+			panic(err)
+		}
+		s := &jsonSchema{}
+		if err = json.Unmarshal(buf, s); err != nil {
+			panic(fmt.Sprintf("schema %s: %s", entry.Name(), err.Error()))
+		}
+		ret[s.ID] = s
+	}
+	return ret
+}
+
+// ParseEntities decodes the YAML or JSON documents read from the provided reader into their generic map form
+func ParseEntities(reader io.Reader) ([]map[string]interface{}, error) {
+	entities := []map[string]interface{}{}
+	decoder := utilyaml.NewYAMLOrJSONDecoder(reader, 4096)
+	for {
+		entity := map[string]interface{}{}
+		err := decoder.Decode(&entity)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return entities, err
+		}
+		if len(entity) == 0 {
+			continue
+		}
+		entities = append(entities, entity)
+	}
+	return entities, nil
+}
+
+// ValidateObject converts one of the Entity types from this package to its JSON form and then validates it
+func ValidateObject(obj interface{}) error {
+	buf, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	entity := map[string]interface{}{}
+	err = json.Unmarshal(buf, &entity)
+	if err != nil {
+		return err
+	}
+	return errors.Join(ValidateEntity(entity)...)
+}
+
+// ValidateEntity checks an entity against the embedded catalog-model schema for its kind as well as the naming rules
+// the Backstage processors apply, returning every violation found.
+func ValidateEntity(entity map[string]interface{}) []error {
+	errs := []error{}
+	kind, _ := entity["kind"].(string)
+	schemaID, ok := kindSchemas[kind]
+	if !ok {
+		schemaID = ENTITY_SCHEMA
+	}
+	errs = append(errs, schemas[schemaID].validate("", entity)...)
+
+	metadata, ok := entity["metadata"].(map[string]interface{})
+	if !ok {
+		return errs
+	}
+	if name, ok := metadata["name"].(string); ok && len(name) > 0 {
+		if err := ValidateName(name); err != nil {
+			errs = append(errs, fmt.Errorf("metadata.name: %s", err.Error()))
+		}
+	}
+	if namespace, ok := metadata["namespace"].(string); ok && len(namespace) > 0 {
+		if err := ValidateNamespace(namespace); err != nil {
+			errs = append(errs, fmt.Errorf("metadata.namespace: %s", err.Error()))
+		}
+	}
+	return errs
+}
+
+// ValidateName applies the Backstage rules for an entity name: at most 63 characters from [a-zA-Z0-9-_.], starting
+// and ending with an alphanumeric, and no consecutive underscores.
+func ValidateName(name string) error {
+	switch {
+	case len(name) > NAME_MAX_LENGTH:
+		return fmt.Errorf("%q is %d characters long, the maximum is %d", name, len(name), NAME_MAX_LENGTH)
+	case !namePattern.MatchString(name):
+		return fmt.Errorf("%q must consist of [a-zA-Z0-9-_.] and start and end with an alphanumeric character", name)
+	case strings.Contains(name, "__"):
+		return fmt.Errorf("%q must not contain consecutive underscores", name)
+	}
+	return nil
+}
+
+// ValidateNamespace applies the Backstage rule for a namespace, a lowercase DNS label
+func ValidateNamespace(namespace string) error {
+	if len(namespace) > NAME_MAX_LENGTH || !namespacePattern.MatchString(namespace) {
+		return fmt.Errorf("%q must be a lowercase DNS label of at most %d characters", namespace, NAME_MAX_LENGTH)
+	}
+	return nil
+}
+
+func (s *jsonSchema) validate(path string, value interface{}) []error {
+	errs := []error{}
+	if len(s.Ref) > 0 {
+		ref, ok := schemas[s.Ref]
+		if !ok {
+			return append(errs, fmt.Errorf("%s: unknown schema reference %s", displayPath(path), s.Ref))
+		}
+		errs = append(errs, ref.validate(path, value)...)
+	}
+	for _, sub := range s.AllOf {
+		errs = append(errs, sub.validate(path, value)...)
+	}
+	if len(s.Enum) > 0 && !enumContains(s.Enum, value) {
+		errs = append(errs, fmt.Errorf("%s: %v is not one of %v", displayPath(path), value, s.Enum))
+	}
+
+	switch s.Type {
+	case "":
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return append(errs, fmt.Errorf("%s: expected an object", displayPath(path)))
+		}
+		errs = append(errs, s.validateObject(path, obj)...)
+	case "array":
+		arr, ok := value.([]interface{})
+		if !ok {
+			return append(errs, fmt.Errorf("%s: expected an array", displayPath(path)))
+		}
+		if s.Items != nil {
+			for i, item := range arr {
+				errs = append(errs, s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item)...)
+			}
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return append(errs, fmt.Errorf("%s: expected a string", displayPath(path)))
+		}
+		if s.MinLength != nil && len(str) < *s.MinLength {
+			errs = append(errs, fmt.Errorf("%s: must be at least %d characters long", displayPath(path), *s.MinLength))
+		}
+	}
+	return errs
+}
+
+func (s *jsonSchema) validateObject(path string, obj map[string]interface{}) []error {
+	errs := []error{}
+	for _, required := range s.Required {
+		if _, ok := obj[required]; !ok {
+			errs = append(errs, fmt.Errorf("%s: missing required property %q", displayPath(path), required))
+		}
+	}
+
+	var additional *jsonSchema
+	allowAdditional := true
+	if len(s.AdditionalProperties) > 0 {
+		if err := json.Unmarshal(s.AdditionalProperties, &allowAdditional); err != nil {
+			allowAdditional = true
+			additional = &jsonSchema{}
+			_ = json.Unmarshal(s.AdditionalProperties, additional)
+		}
+	}
+
+	// sort the keys so the errors come back in a stable order
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		childPath := key
+		if len(path) > 0 {
+			childPath = path + "." + key
+		}
+		prop, ok := s.Properties[key]
+		switch {
+		case ok:
+			errs = append(errs, prop.validate(childPath, obj[key])...)
+		case additional != nil:
+			errs = append(errs, additional.validate(childPath, obj[key])...)
+		case !allowAdditional:
+			errs = append(errs, fmt.Errorf("%s: unexpected property", childPath))
+		}
+	}
+	return errs
+}
+
+func enumContains(enum []interface{}, value interface{}) bool {
+	for _, e := range enum {
+		if reflect.DeepEqual(e, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func displayPath(path string) string {
+	if len(path) == 0 {
+		return "<root>"
+	}
+	return path
+}
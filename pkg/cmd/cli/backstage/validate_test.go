@@ -0,0 +1,189 @@
+package backstage
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseEntities(t *testing.T) {
+	entities, err := ParseEntities(strings.NewReader(validEntities))
+	AssertError(t, err)
+	AssertEqual(t, 3, len(entities))
+}
+
+func TestValidateEntity(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		yaml   string
+		errStr []string
+	}{
+		{
+			name: "valid",
+			yaml: validEntities,
+		},
+		{
+			name:   "missing api definition",
+			yaml:   strings.ReplaceAll(validEntities, "  definition: no-definition-yet\n", ""),
+			errStr: []string{`spec: missing required property "definition"`},
+		},
+		{
+			name:   "empty api definition",
+			yaml:   strings.ReplaceAll(validEntities, "  definition: no-definition-yet\n", "  definition: \"\"\n"),
+			errStr: []string{"spec.definition: must be at least 1 characters long"},
+		},
+		{
+			name:   "bad apiVersion for kind",
+			yaml:   strings.ReplaceAll(validEntities, "apiVersion: backstage.io/v1alpha1\nkind: Resource", "apiVersion: backstage.io/v2\nkind: Resource"),
+			errStr: []string{"apiVersion: backstage.io/v2 is not one of"},
+		},
+		{
+			name:   "unexpected top level field",
+			yaml:   strings.ReplaceAll(validEntities, "kind: Component\n", "kind: Component\nfoo: bar\n"),
+			errStr: []string{"foo: unexpected property"},
+		},
+		{
+			name:   "double underscore",
+			yaml:   strings.ReplaceAll(validEntities, "name: default_is-1\nspec", "name: default__is-1\nspec"),
+			errStr: []string{"must not contain consecutive underscores"},
+		},
+		{
+			name:   "too long",
+			yaml:   strings.ReplaceAll(validEntities, "name: default_is-1\nspec", "name: "+strings.Repeat("a", 64)+"\nspec"),
+			errStr: []string{"is 64 characters long"},
+		},
+		{
+			name:   "bad characters",
+			yaml:   strings.ReplaceAll(validEntities, "name: default_is-1\nspec", "name: default:is-1\nspec"),
+			errStr: []string{"must consist of [a-zA-Z0-9-_.]"},
+		},
+		{
+			name: "valid system",
+			yaml: validSystem,
+		},
+		{
+			name:   "system without owner",
+			yaml:   strings.ReplaceAll(validSystem, "  owner: user:owner\n", ""),
+			errStr: []string{`spec: missing required property "owner"`},
+		},
+		{
+			name:   "bad namespace",
+			yaml:   strings.ReplaceAll(validEntities, "name: default_is-1\nspec", "name: default_is-1\n  namespace: My_NS\nspec"),
+			errStr: []string{"metadata.namespace"},
+		},
+		{
+			name: "consecutive hyphens in namespace",
+			yaml: strings.ReplaceAll(validEntities, "name: default_is-1\nspec", "name: default_is-1\n  namespace: a--b\nspec"),
+		},
+	} {
+		entities, err := ParseEntities(strings.NewReader(tc.yaml))
+		AssertError(t, err)
+		errs := []error{}
+		for _, entity := range entities {
+			errs = append(errs, ValidateEntity(entity)...)
+		}
+		if len(tc.errStr) == 0 && len(errs) > 0 {
+			t.Errorf("%s: unexpected errors %v", tc.name, errs)
+		}
+		if len(tc.errStr) > 0 && len(errs) == 0 {
+			t.Errorf("%s: expected errors", tc.name)
+		}
+		for _, str := range tc.errStr {
+			found := false
+			for _, e := range errs {
+				if strings.Contains(e.Error(), str) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("%s: expected an error containing %q in %v", tc.name, str, errs)
+			}
+		}
+	}
+}
+
+func TestValidateObject(t *testing.T) {
+	api := &ApiEntityV1alpha1{
+		Kind:       KindAPI,
+		ApiVersion: VERSION,
+		Entity: Entity{
+			Metadata: EntityMeta{Name: "my-api"},
+		},
+		Spec: &ApiEntityV1alpha1Spec{
+			Type:      API_TYPE,
+			Lifecycle: "production",
+			Owner:     "user:owner",
+		},
+	}
+	err := ValidateObject(api)
+	if err == nil {
+		t.Error("expected error for empty definition")
+	}
+	api.Spec.Definition = API_DEFINITION_PLACEHOLDER
+	AssertError(t, ValidateObject(api))
+}
+
+const (
+	validEntities = `apiVersion: backstage.io/v1alpha1
+kind: Component
+metadata:
+  annotations:
+    backstage.io/techdocs-ref: ./
+  description: KServe instance default:is-1
+  name: default_is-1
+spec:
+  dependsOn:
+  - resource:default_is-1
+  - api:default_is-1
+  lifecycle: lifecycle
+  owner: user:owner
+  profile:
+    displayName: The default_is-1 model server
+  providesApis:
+  - default_is-1
+  type: model-server
+---
+apiVersion: backstage.io/v1alpha1
+kind: Resource
+metadata:
+  annotations:
+    backstage.io/techdocs-ref: resource/
+  description: KServe instance default:is-1
+  name: default_is-1
+spec:
+  dependencyOf:
+  - component:default_is-1
+  lifecycle: lifecycle
+  owner: user:owner
+  profile:
+    displayName: The default_is-1 ai model
+  type: api-model
+---
+apiVersion: backstage.io/v1alpha1
+kind: API
+metadata:
+  annotations:
+    backstage.io/techdocs-ref: api/
+  description: KServe instance default:is-1
+  name: default_is-1
+spec:
+  definition: no-definition-yet
+  dependencyOf:
+  - component:default_is-1
+  lifecycle: lifecycle
+  owner: user:owner
+  profile:
+    displayName: The default_is-1 openapi
+  type: openapi
+`
+
+	validSystem = `apiVersion: backstage.io/v1alpha1
+kind: System
+metadata:
+  name: my-ds-project
+spec:
+  owner: user:owner
+  profile:
+    displayName: The my-ds-project serving environment
+  type: model-serving-environment
+`
+)
@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"github.com/gabemontero/backstage-ai-cli/pkg/cmd/cli/backstage"
+	"github.com/gabemontero/backstage-ai-cli/pkg/config"
+	"github.com/gabemontero/backstage-ai-cli/test/stub"
+	"strings"
+	"testing"
+)
+
+func TestDeleteLocations(t *testing.T) {
+	newClient := func() *fakeCatalog {
+		return &fakeCatalog{
+			locations: map[string]backstage.Location{
+				"my-location-id":   {ID: "my-location-id", Type: "url", Target: "https://my-repo/my.yaml"},
+				"your-location-id": {ID: "your-location-id", Type: "url", Target: "https://my-repo/your.yaml"},
+			},
+			entities: map[string][]backstage.Entity{"https://my-repo/my.yaml": {
+				{Kind: backstage.KindComponent, Metadata: backstage.EntityMeta{Name: "my-model"}},
+				{Kind: backstage.KindAPI, Metadata: backstage.EntityMeta{Name: "my-model"}},
+			}},
+		}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		cfg      *config.Config
+		ids      []string
+		input    string
+		deleted  []string
+		preview  []string
+		output   string
+		hasError bool
+	}{
+		{
+			name:    "confirmed",
+			cfg:     &config.Config{},
+			ids:     []string{"my-location-id"},
+			input:   "y\n",
+			deleted: []string{"my-location-id"},
+			preview: []string{"Backstage location my-location-id (url:https://my-repo/my.yaml) will be deleted along with 2 entities:", "    Component:default/my-model", "    API:default/my-model", "Delete? [y/N]: "},
+			output:  "Backstage location my-location-id deleted",
+		},
+		{
+			name:    "declined",
+			cfg:     &config.Config{},
+			ids:     []string{"my-location-id"},
+			input:   "n\n",
+			preview: []string{"will be deleted along with 2 entities"},
+			output:  "nothing deleted",
+		},
+		{
+			name:     "no input to confirm with",
+			cfg:      &config.Config{},
+			ids:      []string{"my-location-id"},
+			hasError: true,
+		},
+		{
+			name:    "multiple ids and a target with --yes",
+			cfg:     &config.Config{AssumeYes: true, DeleteTarget: "https://my-repo/your.yaml"},
+			ids:     []string{"my-location-id"},
+			deleted: []string{"my-location-id", "your-location-id"},
+			preview: []string{"Backstage location your-location-id (url:https://my-repo/your.yaml) will be deleted along with 0 entities:"},
+			output:  "Backstage location your-location-id deleted",
+		},
+		{
+			name:     "unknown target",
+			cfg:      &config.Config{AssumeYes: true, DeleteTarget: "https://my-repo/unknown.yaml"},
+			hasError: true,
+		},
+		{
+			name:     "unknown id",
+			cfg:      &config.Config{AssumeYes: true},
+			ids:      []string{"unknown-location-id"},
+			hasError: true,
+		},
+		{
+			name:     "no ids",
+			cfg:      &config.Config{AssumeYes: true},
+			hasError: true,
+		},
+	} {
+		client := newClient()
+		out := &bytes.Buffer{}
+		str, err := deleteLocations(context.Background(), client, tc.cfg, tc.ids, strings.NewReader(tc.input), out)
+		if tc.hasError {
+			if err == nil {
+				t.Errorf("%s: expected an error", tc.name)
+			}
+			assertEqual(t, 0, len(client.deleted))
+			continue
+		}
+		assertContains(t, err, str, tc.output)
+		assertContains(t, nil, out.String(), tc.preview...)
+		if len(tc.deleted) > 0 || len(client.deleted) > 0 {
+			assertEqual(t, tc.deleted, client.deleted)
+		}
+	}
+}
+
+func TestDeleteModelArgs(t *testing.T) {
+	_, _, err := stub.ExecuteCommand(NewCmd(), "delete-model")
+	if err == nil || !strings.Contains(err.Error(), "requires one or more location IDs") {
+		t.Errorf("expected an argument error but got %v", err)
+	}
+}
+
+func TestDeleteEntities(t *testing.T) {
+	client := &fakeCatalog{}
+	str, err := deleteEntities(context.Background(), client, []string{"component:default/my-model", "api:ai/my-model"})
+	assertContains(t, err, str, "component:default/my-model (uid uid-my-model) deleted", "api:ai/my-model (uid uid-my-model) deleted")
+
+	if _, err = deleteEntities(context.Background(), client, []string{"my-model"}); err == nil {
+		t.Error("expected an error for the reference without a kind")
+	}
+}
+
+func TestPruneOrphans(t *testing.T) {
+	types := backstage.EntityTypes{Component: []string{"model-server"}, Resource: []string{"ai-model"}, API: []string{"openapi"}, System: []string{"model-serving-environment"}}
+	filters := strings.Join([]string{
+		"kind=component,spec.type=model-server,metadata.annotations.backstage.io/orphan=true",
+		"kind=resource,spec.type=ai-model,metadata.annotations.backstage.io/orphan=true",
+		"kind=api,spec.type=openapi,metadata.annotations.backstage.io/orphan=true",
+		"kind=system,spec.type=model-serving-environment,metadata.annotations.backstage.io/orphan=true",
+	}, ";")
+	newClient := func() *fakeCatalog {
+		return &fakeCatalog{query: map[string][]backstage.Entity{
+			// orphaned Users and Groups are not queried for, so they are never deleted
+			"metadata.annotations.backstage.io/orphan=true": {
+				{Kind: "User", Metadata: backstage.EntityMeta{Name: "someone", UID: "uid-0"}},
+			},
+			filters: {
+				{Kind: backstage.KindComponent, Metadata: backstage.EntityMeta{Name: "old-model", UID: "uid-1"}},
+				{Kind: backstage.KindAPI, Metadata: backstage.EntityMeta{Name: "old-model", UID: "uid-2"}},
+			},
+		}}
+	}
+
+	client := newClient()
+	out := &bytes.Buffer{}
+	str, err := pruneOrphans(context.Background(), client, &config.Config{}, types, strings.NewReader("y\n"), out)
+	assertContains(t, err, str, "Component:default/old-model (uid uid-1) deleted", "API:default/old-model (uid uid-2) deleted")
+	assertContains(t, nil, out.String(), "2 orphaned entities will be deleted:", "    Component:default/old-model", "Delete? [y/N]: ")
+	assertEqual(t, []string{"uid-1", "uid-2"}, client.deleted)
+
+	client = newClient()
+	str, err = pruneOrphans(context.Background(), client, &config.Config{}, types, strings.NewReader("n\n"), &bytes.Buffer{})
+	assertContains(t, err, str, "nothing deleted")
+	assertEqual(t, 0, len(client.deleted))
+
+	client = newClient()
+	out.Reset()
+	str, err = pruneOrphans(context.Background(), client, &config.Config{AssumeYes: true}, types, strings.NewReader(""), out)
+	assertContains(t, err, str, "Component:default/old-model (uid uid-1) deleted")
+	if strings.Contains(out.String(), "Delete?") {
+		t.Errorf("expected no confirmation prompt with --yes, got %q", out.String())
+	}
+
+	str, err = pruneOrphans(context.Background(), &fakeCatalog{}, &config.Config{}, types, strings.NewReader(""), &bytes.Buffer{})
+	assertContains(t, err, str, "no orphaned entities found")
+}
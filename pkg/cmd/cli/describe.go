@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/gabemontero/backstage-ai-cli/pkg/cmd/cli/backstage"
+	"sort"
+	"strings"
+)
+
+// treeNode is a line of the describe output along with the lines nested under it
+type treeNode struct {
+	label    string
+	children []*treeNode
+}
+
+func (n *treeNode) add(label string) *treeNode {
+	child := &treeNode{label: label}
+	n.children = append(n.children, child)
+	return child
+}
+
+func (n *treeNode) write(buffer *bytes.Buffer, indent string) {
+	for i, child := range n.children {
+		branch, nested := "├── ", "│   "
+		if i == len(n.children)-1 {
+			branch, nested = "└── ", "    "
+		}
+		fmt.Fprintf(buffer, "%s%s%s\n", indent, branch, child.label)
+		child.write(buffer, indent+nested)
+	}
+}
+
+// describeEntity renders the entity as a tree of its spec, the entities it relates to, the locations that led the
+// catalog to it, and any errors from processing it
+func describeEntity(ctx context.Context, client backstage.CatalogClient, ref string) (string, error) {
+	entity, err := client.GetEntity(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	ancestry, err := client.GetEntityAncestry(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+
+	root := &treeNode{label: backstage.EntityRef(*entity)}
+	if len(entity.Metadata.Title) > 0 {
+		root.label = fmt.Sprintf("%s (%s)", root.label, entity.Metadata.Title)
+	}
+
+	spec := root.add("Spec")
+	for _, field := range []string{"type", "lifecycle", "owner", "system"} {
+		if value, ok := entity.Spec[field].(string); ok && len(value) > 0 {
+			spec.add(fmt.Sprintf("%s: %s", field, value))
+		}
+	}
+
+	relations := root.add("Relations")
+	byType := map[string][]string{}
+	for _, relation := range entity.Relations {
+		byType[relation.Type] = append(byType[relation.Type], relation.TargetRef)
+	}
+	types := []string{}
+	for t := range byType {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		node := relations.add(t)
+		for _, target := range byType[t] {
+			node.add(target)
+		}
+	}
+
+	if len(relations.children) == 0 {
+		relations.label = "Relations: none"
+	}
+
+	imported := root.add("Imported by")
+	if location, ok := entity.Metadata.Annotations[backstage.MANAGED_BY_LOCATION_ANNOTATION]; ok {
+		imported.label = fmt.Sprintf("Imported by %s", location)
+	}
+	addAncestors(imported, ancestry, strings.ToLower(ancestry.RootEntityRef), map[string]bool{})
+
+	status := root.add("Status")
+	if entity.Status != nil {
+		for _, item := range entity.Status.Items {
+			node := status.add(fmt.Sprintf("%s %s: %s", item.Level, item.Type, item.Message))
+			if item.Error != nil {
+				node.add(fmt.Sprintf("%s: %s", item.Error.Name, item.Error.Message))
+			}
+		}
+	}
+	if len(status.children) == 0 {
+		status.label = "Status: no processing errors"
+	}
+
+	buffer := &bytes.Buffer{}
+	fmt.Fprintln(buffer, root.label)
+	root.write(buffer, "")
+	return buffer.String(), nil
+}
+
+// addAncestors nests the parents of the ref under the node, following the ancestry up to the entities without parents
+func addAncestors(node *treeNode, ancestry *backstage.EntityAncestry, ref string, seen map[string]bool) {
+	if seen[ref] {
+		return
+	}
+	seen[ref] = true
+	for _, item := range ancestry.Items {
+		if strings.ToLower(backstage.EntityRef(item.Entity)) != ref {
+			continue
+		}
+		for _, parent := range item.ParentEntityRefs {
+			label := parent
+			if target := ancestorTarget(ancestry, strings.ToLower(parent)); len(target) > 0 {
+				label = fmt.Sprintf("%s (%s)", parent, target)
+			}
+			addAncestors(node.add(label), ancestry, strings.ToLower(parent), seen)
+		}
+	}
+}
+
+// ancestorTarget is the target of a Location entity in the ancestry, which says more than its generated name
+func ancestorTarget(ancestry *backstage.EntityAncestry, ref string) string {
+	for _, item := range ancestry.Items {
+		if strings.ToLower(backstage.EntityRef(item.Entity)) == ref {
+			if target, ok := item.Entity.Spec["target"].(string); ok {
+				return target
+			}
+		}
+	}
+	return ""
+}
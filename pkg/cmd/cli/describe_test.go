@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"context"
+	"github.com/gabemontero/backstage-ai-cli/pkg/cmd/cli/backstage"
+	"testing"
+)
+
+const describeOutput = `Component:default/my-model (My Model)
+├── Spec
+│   ├── type: model-server
+│   ├── lifecycle: production
+│   └── owner: user:default/exampleuser
+├── Relations
+│   ├── dependsOn
+│   │   └── resource:default/my-model-v1
+│   ├── ownedBy
+│   │   └── user:default/exampleuser
+│   └── providesApi
+│       ├── api:default/my-model-rest
+│       └── api:default/my-model-grpc
+├── Imported by url:https://my-repo/my-model/catalog-info.yaml
+│   └── location:default/generated-abc (https://my-repo/my-model/catalog-info.yaml)
+│       └── location:default/generated-root (https://my-repo/catalog-all.yaml)
+└── Status
+    └── error backstage.io/catalog-processing: Policy check failed
+        └── InputError: missing definition
+`
+
+func TestDescribeEntity(t *testing.T) {
+	location := func(name, target string) backstage.Entity {
+		return backstage.Entity{Kind: "Location", Metadata: backstage.EntityMeta{Name: name, Namespace: "default"}, Spec: map[string]interface{}{"target": target}}
+	}
+	client := &fakeCatalog{
+		entity: &backstage.Entity{
+			Kind: backstage.KindComponent,
+			Metadata: backstage.EntityMeta{
+				Name:        "my-model",
+				Namespace:   "default",
+				Title:       "My Model",
+				Annotations: map[string]string{backstage.MANAGED_BY_LOCATION_ANNOTATION: "url:https://my-repo/my-model/catalog-info.yaml"},
+			},
+			Spec: map[string]interface{}{"type": "model-server", "lifecycle": "production", "owner": "user:default/exampleuser"},
+			Relations: []backstage.EntityRelation{
+				{Type: "providesApi", TargetRef: "api:default/my-model-rest"},
+				{Type: "ownedBy", TargetRef: "user:default/exampleuser"},
+				{Type: "dependsOn", TargetRef: "resource:default/my-model-v1"},
+				{Type: "providesApi", TargetRef: "api:default/my-model-grpc"},
+			},
+			Status: &backstage.EntityStatus{Items: []backstage.EntityStatusItem{{
+				Type:    "backstage.io/catalog-processing",
+				Level:   "error",
+				Message: "Policy check failed",
+				Error:   &backstage.EntityStatusItemError{Name: "InputError", Message: "missing definition"},
+			}}},
+		},
+		ancestry: &backstage.EntityAncestry{
+			RootEntityRef: "component:default/my-model",
+			Items: []backstage.EntityAncestryItem{
+				{Entity: backstage.Entity{Kind: backstage.KindComponent, Metadata: backstage.EntityMeta{Name: "my-model", Namespace: "default"}}, ParentEntityRefs: []string{"location:default/generated-abc"}},
+				{Entity: location("generated-abc", "https://my-repo/my-model/catalog-info.yaml"), ParentEntityRefs: []string{"location:default/generated-root"}},
+				{Entity: location("generated-root", "https://my-repo/catalog-all.yaml")},
+			},
+		},
+	}
+
+	str, err := describeEntity(context.Background(), client, "component:default/my-model")
+	assertContains(t, err, str)
+	assertEqual(t, describeOutput, str)
+
+	client.entity = &backstage.Entity{Kind: backstage.KindAPI, Metadata: backstage.EntityMeta{Name: "bare"}}
+	client.ancestry = &backstage.EntityAncestry{RootEntityRef: "api:default/bare"}
+	str, err = describeEntity(context.Background(), client, "api:default/bare")
+	assertContains(t, err, str, "├── Relations: none", "└── Status: no processing errors")
+}
@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gabemontero/backstage-ai-cli/pkg/cmd/cli/backstage"
+	"io"
+	"sigs.k8s.io/yaml"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change, as with 'diff -u'
+const diffContext = 3
+
+// diffGenerated compares each entity new-model generated with the entity of the same kind, namespace, and name in the
+// catalog, rendering a unified diff of their YAML for each one that differs.  The error reports how many differ, so
+// the command exits non-zero on drift.
+func diffGenerated(ctx context.Context, client backstage.CatalogClient, generated io.Reader) (string, error) {
+	entities, err := backstage.ParseEntities(generated)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse the generated entities: %s", err.Error())
+	}
+
+	buffer := &bytes.Buffer{}
+	drift := 0
+	for _, entity := range entities {
+		kind, _ := entity["kind"].(string)
+		metadata, _ := entity["metadata"].(map[string]interface{})
+		if metadata == nil {
+			return buffer.String(), fmt.Errorf("generated %s entity has no metadata", kind)
+		}
+		if namespace, _ := metadata["namespace"].(string); len(namespace) == 0 {
+			metadata["namespace"] = backstage.DEFAULT_NS
+		}
+		ref := fmt.Sprintf("%s:%s/%s", kind, metadata["namespace"], metadata["name"])
+
+		current, from := "", "/dev/null"
+		live, err := client.GetEntity(ctx, ref)
+		notFound := &backstage.NotFoundError{}
+		switch {
+		case errors.As(err, &notFound):
+		case err != nil:
+			return buffer.String(), err
+		default:
+			source, err := backstage.SourceEntity(*live)
+			if err != nil {
+				return buffer.String(), err
+			}
+			current, err = toYAML(source)
+			if err != nil {
+				return buffer.String(), err
+			}
+			from = "catalog/" + ref
+		}
+		wanted, err := toYAML(entity)
+		if err != nil {
+			return buffer.String(), err
+		}
+
+		if diff := unifiedDiff(from, "generated/"+ref, current, wanted); len(diff) > 0 {
+			drift++
+			buffer.WriteString(diff)
+		}
+	}
+
+	if drift > 0 {
+		return buffer.String(), fmt.Errorf("%d of %d generated entities differ from the Backstage Catalog", drift, len(entities))
+	}
+	fmt.Fprintf(buffer, "the %d generated entities match the Backstage Catalog\n", len(entities))
+	return buffer.String(), nil
+}
+
+func toYAML(obj interface{}) (string, error) {
+	buf, err := yaml.Marshal(obj)
+	return string(buf), err
+}
+
+// diffLine is a line of the edit script turning a into b, with op ' ' for a line in both, '-' for a line only in a,
+// and '+' for a line only in b
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// unifiedDiff renders the changes from a to b in the unified format, with the labels in the '---' and '+++' headers,
+// or returns an empty string when they are the same
+func unifiedDiff(fromLabel, toLabel, a, b string) string {
+	if a == b {
+		return ""
+	}
+	script := editScript(splitLines(a), splitLines(b))
+
+	buffer := &bytes.Buffer{}
+	fmt.Fprintf(buffer, "--- %s\n+++ %s\n", fromLabel, toLabel)
+	// aLine and bLine are the lines of a and b before each entry of the script
+	aLine, bLine := make([]int, len(script)+1), make([]int, len(script)+1)
+	for i, line := range script {
+		aLine[i+1], bLine[i+1] = aLine[i], bLine[i]
+		if line.op != '+' {
+			aLine[i+1]++
+		}
+		if line.op != '-' {
+			bLine[i+1]++
+		}
+	}
+
+	for i := 0; i < len(script); {
+		if script[i].op == ' ' {
+			i++
+			continue
+		}
+		// extend the hunk while the next change is close enough for the context around them to touch
+		start, end := max(0, i-diffContext), i
+		for j := i; j < len(script) && j <= end+2*diffContext+1; j++ {
+			if script[j].op != ' ' {
+				end = j
+			}
+		}
+		end = min(len(script), end+diffContext+1)
+
+		fmt.Fprintf(buffer, "@@ -%s +%s @@\n", hunkRange(aLine[start], aLine[end]-aLine[start]),
+			hunkRange(bLine[start], bLine[end]-bLine[start]))
+		for _, line := range script[start:end] {
+			fmt.Fprintf(buffer, "%c%s\n", line.op, line.text)
+		}
+		i = end
+	}
+	return buffer.String()
+}
+
+// hunkRange is the 'start,count' of a hunk header, where start is 1-based, or the line before an empty range
+func hunkRange(before, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", before+1)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
+
+func splitLines(s string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// editScript finds a shortest set of deletes and inserts turning a into b from their longest common subsequence
+func editScript(a, b []string) []diffLine {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	script := []diffLine{}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			script = append(script, diffLine{' ', a[i]})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			script = append(script, diffLine{'-', a[i]})
+			i++
+		default:
+			script = append(script, diffLine{'+', b[j]})
+			j++
+		}
+	}
+	return script
+}
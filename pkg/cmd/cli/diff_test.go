@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"context"
+	"github.com/gabemontero/backstage-ai-cli/pkg/cmd/cli/backstage"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	lines := func(n int, change map[int]string) string {
+		buffer := []string{}
+		for i := 1; i <= n; i++ {
+			line := "line " + string(rune('a'+i-1))
+			if c, ok := change[i]; ok {
+				line = c
+			}
+			if len(line) > 0 {
+				buffer = append(buffer, line)
+			}
+		}
+		return strings.Join(buffer, "\n") + "\n"
+	}
+
+	for _, tc := range []struct {
+		name   string
+		a, b   string
+		expect string
+	}{
+		{
+			name: "same",
+			a:    lines(5, nil),
+			b:    lines(5, nil),
+		},
+		{
+			name: "change in the middle",
+			a:    lines(10, nil),
+			b:    lines(10, map[int]string{5: "line five"}),
+			expect: `--- a
++++ b
+@@ -2,7 +2,7 @@
+ line b
+ line c
+ line d
+-line e
++line five
+ line f
+ line g
+ line h
+`,
+		},
+		{
+			name: "separate hunks",
+			a:    lines(20, nil),
+			b:    lines(20, map[int]string{2: "", 19: "line s2"}),
+			expect: `--- a
++++ b
+@@ -1,5 +1,4 @@
+ line a
+-line b
+ line c
+ line d
+ line e
+@@ -16,5 +15,5 @@
+ line p
+ line q
+ line r
+-line s
++line s2
+ line t
+`,
+		},
+		{
+			name: "changes merged into one hunk",
+			a:    lines(12, nil),
+			b:    lines(12, map[int]string{3: "line 3", 9: "line 9"}),
+			expect: `--- a
++++ b
+@@ -1,12 +1,12 @@
+ line a
+ line b
+-line c
++line 3
+ line d
+ line e
+ line f
+ line g
+ line h
+-line i
++line 9
+ line j
+ line k
+ line l
+`,
+		},
+		{
+			name: "new file",
+			a:    "",
+			b:    "kind: API\nmetadata:\n",
+			expect: `--- a
++++ b
+@@ -0,0 +1,2 @@
++kind: API
++metadata:
+`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assertEqual(t, tc.expect, unifiedDiff("a", "b", tc.a, tc.b))
+		})
+	}
+}
+
+const generatedYAML = `apiVersion: backstage.io/v1alpha1
+kind: Component
+metadata:
+  name: my-model
+  tags:
+  - genai
+  - vllm
+spec:
+  lifecycle: production
+  owner: user:exampleuser
+  type: model-server
+---
+apiVersion: backstage.io/v1alpha1
+kind: API
+metadata:
+  name: my-model
+spec:
+  definition: no-definition-yet
+  lifecycle: production
+  owner: user:exampleuser
+  type: openapi
+`
+
+const generatedDiff = `--- catalog/Component:default/my-model
++++ generated/Component:default/my-model
+@@ -5,6 +5,7 @@
+   namespace: default
+   tags:
+   - genai
++  - vllm
+ spec:
+   lifecycle: production
+   owner: user:exampleuser
+--- /dev/null
++++ generated/API:default/my-model
+@@ -0,0 +1,10 @@
++apiVersion: backstage.io/v1alpha1
++kind: API
++metadata:
++  name: my-model
++  namespace: default
++spec:
++  definition: no-definition-yet
++  lifecycle: production
++  owner: user:exampleuser
++  type: openapi
+`
+
+func TestDiffGenerated(t *testing.T) {
+	live := &backstage.Entity{
+		ApiVersion: backstage.VERSION,
+		Kind:       backstage.KindComponent,
+		Metadata: backstage.EntityMeta{
+			UID:         "uid-my-model",
+			Etag:        "etag",
+			Name:        "my-model",
+			Namespace:   "default",
+			Tags:        []string{"genai"},
+			Annotations: map[string]string{backstage.MANAGED_BY_LOCATION_ANNOTATION: "url:https://my-repo/catalog-info.yaml"},
+		},
+		Spec:      map[string]interface{}{"type": "model-server", "lifecycle": "production", "owner": "user:exampleuser"},
+		Relations: []backstage.EntityRelation{{Type: "ownedBy", TargetRef: "user:default/exampleuser"}},
+	}
+	client := &fakeCatalog{byRef: map[string]*backstage.Entity{"Component:default/my-model": live}}
+
+	str, err := diffGenerated(context.Background(), client, strings.NewReader(generatedYAML))
+	assertEqual(t, generatedDiff, str)
+	if err == nil || err.Error() != "2 of 2 generated entities differ from the Backstage Catalog" {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	live.Metadata.Tags = []string{"genai", "vllm"}
+	client.byRef["API:default/my-model"] = &backstage.Entity{
+		ApiVersion: backstage.VERSION,
+		Kind:       backstage.KindAPI,
+		Metadata:   backstage.EntityMeta{Name: "my-model", Namespace: "default"},
+		Spec:       map[string]interface{}{"type": "openapi", "lifecycle": "production", "owner": "user:exampleuser", "definition": "no-definition-yet"},
+	}
+	str, err = diffGenerated(context.Background(), client, strings.NewReader(generatedYAML))
+	assertContains(t, err, str, "the 2 generated entities match the Backstage Catalog")
+}
@@ -0,0 +1,207 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/gabemontero/backstage-ai-cli/pkg/cmd/cli/backstage"
+	"github.com/gabemontero/backstage-ai-cli/pkg/util"
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// exportUnmanagedDir holds the exported entities that no location manages, such as those created through the API
+const exportUnmanagedDir = "unmanaged"
+
+var locationDirChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// locationDir turns a location such as 'url:https://github.com/my-org/my-repo/blob/main/catalog-info.yaml' into the
+// name of the directory its entities are exported to
+func locationDir(location string) string {
+	if len(location) == 0 {
+		return exportUnmanagedDir
+	}
+	if _, target, found := strings.Cut(location, ":"); found {
+		location = target
+	}
+	if _, rest, found := strings.Cut(location, "://"); found {
+		location = rest
+	}
+	return strings.Trim(locationDirChars.ReplaceAllString(location, "-"), "-.")
+}
+
+// locationDirs maps each location to its export directory.  When the names of several locations come out the same,
+// each gets a hash of its location appended, so one does not overwrite the catalog-info.yaml of another.
+func locationDirs(locations []string) map[string]string {
+	byDir := map[string][]string{}
+	for _, location := range locations {
+		dir := locationDir(location)
+		byDir[dir] = append(byDir[dir], location)
+	}
+	dirs := map[string]string{}
+	for dir, group := range byDir {
+		for _, location := range group {
+			if len(group) == 1 {
+				dirs[location] = dir
+				continue
+			}
+			hash := fnv.New32a()
+			hash.Write([]byte(location))
+			dirs[location] = fmt.Sprintf("%s-%08x", dir, hash.Sum32())
+		}
+	}
+	return dirs
+}
+
+// exportFilters pairs each type to export with the kinds it is a type of, the same way as EntityTypes.Filters, so an
+// entity of another kind that happens to share a type is left out.  A type outside of the vocabulary could be any of
+// the kinds.
+func exportFilters(entityTypes backstage.EntityTypes, types []string) []string {
+	filters := []string{}
+	kinds := []string{"component", "resource", "api", "system"}
+	seen := map[string]bool{}
+	for _, t := range types {
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		matched := false
+		for _, kind := range kinds {
+			if slices.Contains(entityTypes.ForKind(kind), t) {
+				matched = true
+				filters = append(filters, fmt.Sprintf("kind=%s,spec.type=%s", kind, t))
+			}
+		}
+		if matched {
+			continue
+		}
+		for _, kind := range kinds {
+			filters = append(filters, fmt.Sprintf("kind=%s,spec.type=%s", kind, t))
+		}
+	}
+	return filters
+}
+
+// exportEntities writes the entities with the spec types to a catalog-info.yaml per location they were imported from,
+// without the fields the catalog manages, so the files can be imported into another Backstage instance
+func exportEntities(ctx context.Context, client backstage.CatalogClient, entityTypes backstage.EntityTypes, types []string, outputDir string) (string, error) {
+	if len(outputDir) == 0 {
+		return "", fmt.Errorf("export requires --output-dir")
+	}
+	if len(types) == 0 {
+		return "", fmt.Errorf("export requires at least one --type")
+	}
+	entities, err := client.QueryEntities(ctx, exportFilters(entityTypes, types)...)
+	if err != nil {
+		return "", err
+	}
+	if len(entities) == 0 {
+		return fmt.Sprintf("no entities found with spec.type %s\n", strings.Join(types, ", ")), nil
+	}
+
+	byLocation := map[string][]backstage.Entity{}
+	for _, entity := range entities {
+		location := entity.Metadata.Annotations[backstage.MANAGED_BY_LOCATION_ANNOTATION]
+		byLocation[location] = append(byLocation[location], entity)
+	}
+	locations := []string{}
+	for location := range byLocation {
+		locations = append(locations, location)
+	}
+	sort.Strings(locations)
+	dirs := locationDirs(locations)
+
+	buffer := &bytes.Buffer{}
+	for _, location := range locations {
+		group := byLocation[location]
+		sort.Slice(group, func(i, j int) bool {
+			return backstage.EntityRef(group[i]) < backstage.EntityRef(group[j])
+		})
+		file, err := writeExport(filepath.Join(outputDir, dirs[location]), location, group)
+		if err != nil {
+			return buffer.String(), err
+		}
+		from := location
+		if len(from) == 0 {
+			from = "no location"
+		}
+		fmt.Fprintf(buffer, "%s: %d entities from %s\n", file, len(group), from)
+	}
+	return buffer.String(), nil
+}
+
+func writeExport(dir, location string, entities []backstage.Entity) (string, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return "", err
+	}
+	fileName := filepath.Join(dir, backstage.CATALOG_INFO_FILE)
+	file, err := os.Create(fileName)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	if len(location) > 0 {
+		fmt.Fprintf(file, "# exported from %s\n", location)
+	}
+	for _, entity := range entities {
+		source, err := backstage.SourceEntity(entity)
+		if err != nil {
+			return "", err
+		}
+		err = util.PrintYaml(source, true, file)
+		if err != nil {
+			return "", err
+		}
+	}
+	return fileName, nil
+}
+
+// restoreEntities registers a location for each catalog-info.yaml under the directory written by export.  With a base
+// URL, where the directory was pushed to Git for instance, the locations are URLs under it; otherwise they are 'file'
+// locations, which Backstage can only read when the directory is on the host it runs on.
+func restoreEntities(ctx context.Context, client backstage.CatalogClient, dir, baseURL string) (string, error) {
+	files := []string{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && d.Name() == backstage.CATALOG_INFO_FILE {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("no %s files found under %s", backstage.CATALOG_INFO_FILE, dir)
+	}
+
+	buffer := &bytes.Buffer{}
+	for _, file := range files {
+		location := backstage.Location{Type: "file"}
+		if len(baseURL) > 0 {
+			rel, err := filepath.Rel(dir, file)
+			if err != nil {
+				return buffer.String(), err
+			}
+			location = backstage.Location{Type: "url", Target: strings.TrimSuffix(baseURL, "/") + "/" + filepath.ToSlash(rel)}
+		} else if location.Target, err = filepath.Abs(file); err != nil {
+			return buffer.String(), err
+		}
+		str, err := formatImport(client.RegisterLocation(ctx, location))
+		buffer.WriteString(str)
+		if err != nil {
+			return buffer.String(), err
+		}
+	}
+	return buffer.String(), nil
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"context"
+	"github.com/gabemontero/backstage-ai-cli/pkg/cmd/cli/backstage"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const exportedYAML = `# exported from url:https://github.com/my-org/my-repo/blob/main/catalog-info.yaml
+apiVersion: backstage.io/v1alpha1
+kind: API
+metadata:
+  annotations:
+    backstage.io/techdocs-ref: dir:.
+  name: my-model
+  namespace: default
+spec:
+  type: openapi
+---
+apiVersion: backstage.io/v1alpha1
+kind: Component
+metadata:
+  name: my-model
+  namespace: default
+spec:
+  type: model-server
+---
+`
+
+func TestLocationDir(t *testing.T) {
+	for location, dir := range map[string]string{
+		"url:https://github.com/my-org/my-repo/blob/main/catalog-info.yaml": "github.com-my-org-my-repo-blob-main-catalog-info.yaml",
+		"file:/var/catalog/models.yaml":                                     "var-catalog-models.yaml",
+		"":                                                                  exportUnmanagedDir,
+	} {
+		assertEqual(t, dir, locationDir(location))
+	}
+}
+
+func TestLocationDirs(t *testing.T) {
+	dirs := locationDirs([]string{"url:https://github.com/my-org/my-repo/catalog-info.yaml", "file:/github.com/my-org/my-repo/catalog-info.yaml", "file:/var/catalog/models.yaml"})
+	assertEqual(t, "var-catalog-models.yaml", dirs["file:/var/catalog/models.yaml"])
+	first, second := dirs["url:https://github.com/my-org/my-repo/catalog-info.yaml"], dirs["file:/github.com/my-org/my-repo/catalog-info.yaml"]
+	if first == second || !strings.HasPrefix(first, "github.com-my-org-my-repo-catalog-info.yaml-") || !strings.HasPrefix(second, "github.com-my-org-my-repo-catalog-info.yaml-") {
+		t.Errorf("expected distinct directories for the colliding locations, got %q and %q", first, second)
+	}
+}
+
+func TestExportFilters(t *testing.T) {
+	assertEqual(t, []string{"kind=component,spec.type=model-server", "kind=resource,spec.type=ai-model", "kind=component,spec.type=custom",
+		"kind=resource,spec.type=custom", "kind=api,spec.type=custom", "kind=system,spec.type=custom"},
+		exportFilters(backstage.DefaultEntityTypes(), []string{"model-server", "ai-model", "model-server", "custom"}))
+}
+
+func TestExportEntities(t *testing.T) {
+	location := "url:https://github.com/my-org/my-repo/blob/main/catalog-info.yaml"
+	entity := func(kind, specType string, annotations map[string]string) backstage.Entity {
+		return backstage.Entity{
+			ApiVersion: backstage.VERSION,
+			Kind:       kind,
+			Metadata:   backstage.EntityMeta{UID: "uid-" + kind, Etag: "etag", Name: "my-model", Namespace: "default", Annotations: annotations},
+			Spec:       map[string]interface{}{"type": specType},
+			Relations:  []backstage.EntityRelation{{Type: "ownedBy", TargetRef: "user:default/exampleuser"}},
+			Status:     &backstage.EntityStatus{},
+		}
+	}
+	client := &fakeCatalog{query: map[string][]backstage.Entity{
+		"kind=component,spec.type=model-server;kind=api,spec.type=openapi": {
+			entity(backstage.KindComponent, "model-server", map[string]string{backstage.MANAGED_BY_LOCATION_ANNOTATION: location}),
+			entity(backstage.KindAPI, "openapi", map[string]string{backstage.MANAGED_BY_LOCATION_ANNOTATION: location, backstage.TECHDOC_REFS: "dir:."}),
+			entity(backstage.KindComponent, "model-server", nil),
+		},
+	}}
+	client.query["kind=component,spec.type=model-server;kind=api,spec.type=openapi"][2].Metadata.Name = "created-by-api"
+
+	dir := t.TempDir()
+	str, err := exportEntities(context.Background(), client, backstage.DefaultEntityTypes(), []string{"model-server", "openapi"}, dir)
+	exported := filepath.Join(dir, "github.com-my-org-my-repo-blob-main-catalog-info.yaml", backstage.CATALOG_INFO_FILE)
+	assertContains(t, err, str, exported+": 2 entities from "+location, filepath.Join(dir, exportUnmanagedDir, backstage.CATALOG_INFO_FILE)+": 1 entities from no location")
+	buf, err := os.ReadFile(exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, exportedYAML, string(buf))
+
+	_, err = exportEntities(context.Background(), client, backstage.DefaultEntityTypes(), []string{"model-server"}, "")
+	if err == nil || !strings.Contains(err.Error(), "requires --output-dir") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestRestoreEntities(t *testing.T) {
+	dir := t.TempDir()
+	for _, sub := range []string{"b-location", "a-location", "a-location/docs"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, file := range []string{"b-location/catalog-info.yaml", "a-location/catalog-info.yaml", "a-location/docs/index.md"} {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(exportedYAML), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	client := &fakeCatalog{}
+	str, err := restoreEntities(context.Background(), client, dir, "https://github.com/my-org/backup/blob/main/")
+	assertContains(t, err, str, "Backstage location id-0 from https://github.com/my-org/backup/blob/main/a-location/catalog-info.yaml created")
+	assertEqual(t, []backstage.Location{
+		{ID: "id-0", Type: "url", Target: "https://github.com/my-org/backup/blob/main/a-location/catalog-info.yaml"},
+		{ID: "id-1", Type: "url", Target: "https://github.com/my-org/backup/blob/main/b-location/catalog-info.yaml"},
+	}, client.imported)
+
+	client = &fakeCatalog{}
+	_, err = restoreEntities(context.Background(), client, dir, "")
+	assertContains(t, err, "")
+	assertEqual(t, backstage.Location{ID: "id-0", Type: "file", Target: filepath.Join(dir, "a-location", backstage.CATALOG_INFO_FILE)}, client.imported[0])
+
+	_, err = restoreEntities(context.Background(), client, filepath.Join(dir, "a-location", "docs"), "")
+	if err == nil || !strings.Contains(err.Error(), "no catalog-info.yaml files found") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"github.com/gabemontero/backstage-ai-cli/pkg/cmd/cli/backstage"
+	"strings"
+)
+
+// fakeCatalog stands in for the Backstage REST client; the CatalogClient methods a test does not set up panic
+type fakeCatalog struct {
+	backstage.CatalogClient
+	locations map[string]backstage.Location
+	entities  map[string][]backstage.Entity
+	etags     map[string]string
+	refreshed []string
+	query     map[string][]backstage.Entity
+	deleted   []string
+	entity    *backstage.Entity
+	ancestry  *backstage.EntityAncestry
+	facets    map[string][]backstage.FacetCount
+	byRef     map[string]*backstage.Entity
+	imported  []backstage.Location
+}
+
+func (f *fakeCatalog) GetLocation(ctx context.Context, args ...string) ([]backstage.Location, error) {
+	location, ok := f.locations[args[0]]
+	if !ok {
+		return nil, fmt.Errorf("location %s not found", args[0])
+	}
+	return []backstage.Location{location}, nil
+}
+
+func (f *fakeCatalog) GetLocationEntities(ctx context.Context, location backstage.Location) ([]backstage.Entity, error) {
+	return f.entities[location.Target], nil
+}
+
+func (f *fakeCatalog) GetEntity(ctx context.Context, ref string) (*backstage.Entity, error) {
+	if f.entity != nil {
+		return f.entity, nil
+	}
+	if f.byRef != nil {
+		entity, ok := f.byRef[ref]
+		if !ok {
+			return nil, &backstage.NotFoundError{Refs: []string{ref}}
+		}
+		return entity, nil
+	}
+	return &backstage.Entity{Metadata: backstage.EntityMeta{Etag: f.etags[ref]}}, nil
+}
+
+func (f *fakeCatalog) RefreshEntity(ctx context.Context, ref string) error {
+	f.refreshed = append(f.refreshed, ref)
+	f.etags[ref] = f.etags[ref] + "-refreshed"
+	return nil
+}
+
+func (f *fakeCatalog) GetLocationByTarget(ctx context.Context, target string) (*backstage.Location, error) {
+	for _, location := range f.locations {
+		if location.Target == target {
+			return &location, nil
+		}
+	}
+	return nil, fmt.Errorf("no Backstage location with the target %s", target)
+}
+
+func (f *fakeCatalog) DeleteLocation(ctx context.Context, id string) error {
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+// QueryEntities answers with the entities set up for the filters joined by ';'
+func (f *fakeCatalog) QueryEntities(ctx context.Context, filters ...string) ([]backstage.Entity, error) {
+	return f.query[strings.Join(filters, ";")], nil
+}
+
+func (f *fakeCatalog) GetEntityFacets(ctx context.Context, facets []string, filters ...string) (map[string][]backstage.FacetCount, error) {
+	result := map[string][]backstage.FacetCount{}
+	for _, facet := range facets {
+		result[facet] = f.facets[facet]
+	}
+	return result, nil
+}
+
+func (f *fakeCatalog) DeleteEntity(ctx context.Context, ref string) (*backstage.Entity, error) {
+	kind, namespace, name, err := backstage.ParseEntityRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	f.deleted = append(f.deleted, ref)
+	return &backstage.Entity{Kind: kind, Metadata: backstage.EntityMeta{Name: name, Namespace: namespace, UID: "uid-" + name}}, nil
+}
+
+func (f *fakeCatalog) DeleteEntityByUID(ctx context.Context, uid string) error {
+	f.deleted = append(f.deleted, uid)
+	return nil
+}
+
+func (f *fakeCatalog) GetEntityAncestry(ctx context.Context, ref string) (*backstage.EntityAncestry, error) {
+	return f.ancestry, nil
+}
+
+func (f *fakeCatalog) RegisterLocation(ctx context.Context, location backstage.Location) (*backstage.ImportResult, error) {
+	location.ID = fmt.Sprintf("id-%d", len(f.imported))
+	f.imported = append(f.imported, location)
+	return &backstage.ImportResult{Location: location}, nil
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gabemontero/backstage-ai-cli/pkg/cmd/cli/backstage"
+)
+
+// formatJSON renders what the Backstage client returned as indented JSON; on an error, whatever was retrieved
+// before the error is still rendered
+func formatJSON(obj interface{}, err error) (string, error) {
+	buf, e := json.MarshalIndent(obj, "", "    ")
+	if e != nil {
+		return "", e
+	}
+	return string(buf), err
+}
+
+func formatImport(result *backstage.ImportResult, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	buffer := &bytes.Buffer{}
+	fmt.Fprintf(buffer, "Backstage location %s from %s created\n", result.Location.ID, result.Location.Target)
+	for _, entity := range result.Entities {
+		fmt.Fprintf(buffer, "    %s\n", backstage.EntityRef(entity))
+	}
+	return buffer.String(), nil
+}
+
+func formatDelete(id string, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Backstage location %s deleted", id), nil
+}
+
+func formatDryRun(result *backstage.ImportResult) string {
+	buffer := &bytes.Buffer{}
+	fmt.Fprintf(buffer, "Dry run of Backstage location %s:%s (already registered: %v)\n", result.Location.Type, result.Location.Target, result.Exists)
+	for _, entity := range result.Entities {
+		fmt.Fprintf(buffer, "    %s: processed\n", backstage.EntityRef(entity))
+	}
+	return buffer.String()
+}
+
+func formatAnalysis(url string, analysis *backstage.LocationAnalysis) string {
+	buffer := &bytes.Buffer{}
+	fmt.Fprintf(buffer, "Analysis of Backstage location url:%s\n", url)
+	for _, file := range analysis.ExistingEntityFiles {
+		fmt.Fprintf(buffer, "    %s: found in %s:%s (registered: %v)\n", backstage.EntityRef(file.Entity), file.Location.Type, file.Location.Target, file.IsRegistered)
+	}
+	for _, gen := range analysis.GenerateEntities {
+		fmt.Fprintf(buffer, "    %s: would need to be generated\n", backstage.EntityRef(gen.Entity))
+	}
+	return buffer.String()
+}
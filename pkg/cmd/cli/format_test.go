@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"fmt"
+	"github.com/gabemontero/backstage-ai-cli/pkg/cmd/cli/backstage"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFormatLocations(t *testing.T) {
+	component := backstage.Entity{Kind: backstage.KindComponent, Metadata: backstage.EntityMeta{Name: "my-component"}}
+	api := backstage.Entity{Kind: backstage.KindAPI, Metadata: backstage.EntityMeta{Name: "my-api", Namespace: "ai"}}
+	location := backstage.Location{ID: "my-location-id", Type: "url", Target: "https://my-repo/my.yaml"}
+
+	str, err := formatImport(&backstage.ImportResult{Location: location, Entities: []backstage.Entity{component, api}}, nil)
+	assertContains(t, err, str, "Backstage location my-location-id from https://my-repo/my.yaml created", "    Component:default/my-component", "    API:ai/my-api")
+
+	_, err = formatImport(nil, fmt.Errorf("post failed"))
+	if err == nil {
+		t.Error("expected the import error to be returned")
+	}
+
+	str, err = formatDelete("my-location-id", nil)
+	assertContains(t, err, str, "Backstage location my-location-id deleted")
+
+	str = formatDryRun(&backstage.ImportResult{Location: location, Entities: []backstage.Entity{component, api}})
+	assertContains(t, nil, str, "already registered: false", "Component:default/my-component: processed", "API:ai/my-api: processed")
+
+	str = formatAnalysis(location.Target, &backstage.LocationAnalysis{
+		ExistingEntityFiles: []backstage.ExistingEntityFile{{Location: location, IsRegistered: true, Entity: component}},
+		GenerateEntities:    []backstage.GenerateEntity{{Entity: api}},
+	})
+	assertContains(t, nil, str, "Component:default/my-component: found in url:https://my-repo/my.yaml (registered: true)", "API:ai/my-api: would need to be generated")
+
+	str, err = formatJSON([]backstage.Location{location}, nil)
+	assertContains(t, err, str, "    {\n        \"id\": \"my-location-id\",")
+}
+
+func assertContains(t *testing.T, err error, str string, subs ...string) {
+	t.Helper()
+	if err != nil {
+		t.Errorf("unexpected error %s", err.Error())
+	}
+	for _, sub := range subs {
+		if !strings.Contains(str, sub) {
+			t.Errorf("expected %q to contain %q", str, sub)
+		}
+	}
+}
+
+func assertEqual(t *testing.T, expected, got interface{}) {
+	t.Helper()
+	if !reflect.DeepEqual(expected, got) {
+		t.Errorf("expected [%v], got [%v]", expected, got)
+	}
+}
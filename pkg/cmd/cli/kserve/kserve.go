@@ -9,7 +9,9 @@ import (
 	"github.com/gabemontero/backstage-ai-cli/pkg/config"
 	"github.com/gabemontero/backstage-ai-cli/pkg/util"
 	serverapiv1beta1 "github.com/kserve/kserve/pkg/apis/serving/v1beta1"
+	kserveconstants "github.com/kserve/kserve/pkg/constants"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
 	"os"
@@ -32,25 +34,39 @@ $ %s new-model kserve <owner> <lifecycle> --kubeconfig=/home/myid/my-kube.json
 # This form will pull in only the InferenceService instances with the names 'inferenceservice1' and 'inferenceservice2'
 # in the 'my-datascience-project'namespace in order to build Catalog Component, Resource, and API Entities.
 $ %s new-model kserve owner lifecycle inferenceservice1 inferenceservice2 --namespace my-datascience-project
+
+# This will also add whether each InferenceService is ready, the state of its model, and how traffic is split across its
+# revisions to the Component annotations and links, and a health section to its TechDocs when --output-dir is set
+$ %s new-model kserve <owner> <lifecycle> --status --output-dir=./catalog
 `
-	sklearn     = "sklearn"
-	xgboost     = "xgboost"
-	tensorflow  = "tensorflow"
-	pytorch     = "pytorch"
-	triton      = "triton"
-	onnx        = "onnx"
-	huggingface = "huggingface"
-	pmml        = "pmml"
-	lightgbm    = "lightgbm"
-	paddle      = "paddle"
+	sklearn      = "sklearn"
+	xgboost      = "xgboost"
+	tensorflow   = "tensorflow"
+	pytorch      = "pytorch"
+	triton       = "triton"
+	onnx         = "onnx"
+	huggingface  = "huggingface"
+	hfModelIdArg = "--model_id="
+	pmml         = "pmml"
+	lightgbm     = "lightgbm"
+	paddle       = "paddle"
+
+	// the labels the model registry puts on the InferenceServices it deploys
+	registeredModelIdLabel = "modelregistry.kubeflow.org/registered-model-id"
+	modelVersionIdLabel    = "modelregistry.kubeflow.org/model-version-id"
 )
 
 type commonPopulator struct {
 	owner     string
 	lifecycle string
+	types     backstage.EntityTypes
 	is        *serverapiv1beta1.InferenceService
 }
 
+func (pop *commonPopulator) GetEntityTypes() backstage.EntityTypes {
+	return pop.types
+}
+
 func (pop *commonPopulator) GetOwner() string {
 	return pop.owner
 }
@@ -81,13 +97,13 @@ func (pop *commonPopulator) GetLinks() []backstage.EntityLink {
 		if componentStatus.URL != nil {
 			links = append(links, backstage.EntityLink{
 				URL:   componentStatus.URL.String() + "/docs",
-				Title: string(componentType) + " FastAPI URL",
+				Title: string(componentType) + " " + backstage.LINK_FASTAPI_URL,
 				Icon:  backstage.LINK_ICON_WEBASSET,
 				Type:  backstage.LINK_TYPE_WEBSITE,
 			})
 			links = append(links, backstage.EntityLink{
 				URL:   componentStatus.URL.String(),
-				Title: string(componentType) + " model serving URL",
+				Title: string(componentType) + " " + backstage.LINK_MODEL_SERVING_URL,
 				Icon:  backstage.LINK_ICON_WEBASSET,
 				Type:  backstage.LINK_TYPE_WEBSITE,
 			})
@@ -95,7 +111,7 @@ func (pop *commonPopulator) GetLinks() []backstage.EntityLink {
 		if componentStatus.RestURL != nil {
 			links = append(links, backstage.EntityLink{
 				URL:   componentStatus.RestURL.String(),
-				Title: string(componentType) + " REST model serving URL",
+				Title: string(componentType) + " " + backstage.LINK_REST_MODEL_SERVING_URL,
 				Icon:  backstage.LINK_ICON_WEBASSET,
 				Type:  backstage.LINK_TYPE_WEBSITE,
 			})
@@ -103,7 +119,7 @@ func (pop *commonPopulator) GetLinks() []backstage.EntityLink {
 		if componentStatus.GrpcURL != nil {
 			links = append(links, backstage.EntityLink{
 				URL:   componentStatus.GrpcURL.String(),
-				Title: string(componentType) + " GRPC model serving URL",
+				Title: string(componentType) + " " + backstage.LINK_GRPC_MODEL_SERVING_URL,
 				Icon:  backstage.LINK_ICON_WEBASSET,
 				Type:  backstage.LINK_TYPE_WEBSITE,
 			})
@@ -112,67 +128,269 @@ func (pop *commonPopulator) GetLinks() []backstage.EntityLink {
 	return links
 }
 
+// predictorFramework maps a framework specific predictor spec to the tag and model format it is known by
+type predictorFramework struct {
+	name string
+	spec func(predictor *serverapiv1beta1.PredictorSpec) *serverapiv1beta1.PredictorExtensionSpec
+}
+
+// predictorFrameworks are the framework specific predictors, of which at most one is set, in alphabetical order
+var predictorFrameworks = []predictorFramework{
+	{huggingface, func(p *serverapiv1beta1.PredictorSpec) *serverapiv1beta1.PredictorExtensionSpec {
+		if p.HuggingFace == nil {
+			return nil
+		}
+		return &p.HuggingFace.PredictorExtensionSpec
+	}},
+	{lightgbm, func(p *serverapiv1beta1.PredictorSpec) *serverapiv1beta1.PredictorExtensionSpec {
+		if p.LightGBM == nil {
+			return nil
+		}
+		return &p.LightGBM.PredictorExtensionSpec
+	}},
+	{onnx, func(p *serverapiv1beta1.PredictorSpec) *serverapiv1beta1.PredictorExtensionSpec {
+		if p.ONNX == nil {
+			return nil
+		}
+		return &p.ONNX.PredictorExtensionSpec
+	}},
+	{paddle, func(p *serverapiv1beta1.PredictorSpec) *serverapiv1beta1.PredictorExtensionSpec {
+		if p.Paddle == nil {
+			return nil
+		}
+		return &p.Paddle.PredictorExtensionSpec
+	}},
+	{pmml, func(p *serverapiv1beta1.PredictorSpec) *serverapiv1beta1.PredictorExtensionSpec {
+		if p.PMML == nil {
+			return nil
+		}
+		return &p.PMML.PredictorExtensionSpec
+	}},
+	{pytorch, func(p *serverapiv1beta1.PredictorSpec) *serverapiv1beta1.PredictorExtensionSpec {
+		if p.PyTorch == nil {
+			return nil
+		}
+		return &p.PyTorch.PredictorExtensionSpec
+	}},
+	{sklearn, func(p *serverapiv1beta1.PredictorSpec) *serverapiv1beta1.PredictorExtensionSpec {
+		if p.SKLearn == nil {
+			return nil
+		}
+		return &p.SKLearn.PredictorExtensionSpec
+	}},
+	{tensorflow, func(p *serverapiv1beta1.PredictorSpec) *serverapiv1beta1.PredictorExtensionSpec {
+		if p.Tensorflow == nil {
+			return nil
+		}
+		return &p.Tensorflow.PredictorExtensionSpec
+	}},
+	{triton, func(p *serverapiv1beta1.PredictorSpec) *serverapiv1beta1.PredictorExtensionSpec {
+		if p.Triton == nil {
+			return nil
+		}
+		return &p.Triton.PredictorExtensionSpec
+	}},
+	{xgboost, func(p *serverapiv1beta1.PredictorSpec) *serverapiv1beta1.PredictorExtensionSpec {
+		if p.XGBoost == nil {
+			return nil
+		}
+		return &p.XGBoost.PredictorExtensionSpec
+	}},
+}
+
+// predictorExtension is the spec shared by all predictors, from the model spec or whichever framework spec is set
+func (pop *commonPopulator) predictorExtension() (string, *serverapiv1beta1.PredictorExtensionSpec) {
+	predictor := &pop.is.Spec.Predictor
+	if predictor.Model != nil {
+		return "", &predictor.Model.PredictorExtensionSpec
+	}
+	for _, framework := range predictorFrameworks {
+		if spec := framework.spec(predictor); spec != nil {
+			return framework.name, spec
+		}
+	}
+	return "", nil
+}
+
+// predictorContainers are the containers whose resources the predictor requests
+func (pop *commonPopulator) predictorContainers() []corev1.Container {
+	containers := append([]corev1.Container{}, pop.is.Spec.Predictor.Containers...)
+	if _, spec := pop.predictorExtension(); spec != nil {
+		containers = append(containers, spec.Container)
+	}
+	return containers
+}
+
+// requestsGPU checks the requests and limits of the predictor for any of the vendor GPU resources, such as nvidia.com/gpu
+func (pop *commonPopulator) requestsGPU() bool {
+	for _, container := range pop.predictorContainers() {
+		for _, resources := range []corev1.ResourceList{container.Resources.Requests, container.Resources.Limits} {
+			for name, quantity := range resources {
+				if strings.HasSuffix(string(name), "/gpu") && !quantity.IsZero() {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (pop *commonPopulator) deploymentMode() string {
+	return pop.is.Annotations[kserveconstants.DeploymentMode]
+}
+
+// GetTags describes the predictor: its framework or model format, the storage URI scheme, runtime, protocol, whether it
+// uses GPUs, its replicas, and deployment mode, along with the explainer type
 func (pop *commonPopulator) GetTags() []string {
-	tags := []string{}
+	candidates := []string{}
 	predictor := pop.is.Spec.Predictor
-	// one and only one predictor spec can be set
+	framework, spec := pop.predictorExtension()
 	switch {
-	case predictor.SKLearn != nil:
-		tags = append(tags, sklearn)
-		fallthrough
-	case predictor.XGBoost != nil:
-		tags = append(tags, xgboost)
-		fallthrough
-	case predictor.Tensorflow != nil:
-		tags = append(tags, tensorflow)
-		fallthrough
-	case predictor.PyTorch != nil:
-		tags = append(tags, pytorch)
-		fallthrough
-	case predictor.Triton != nil:
-		tags = append(tags, triton)
-		fallthrough
-	case predictor.ONNX != nil:
-		tags = append(tags, onnx)
-		fallthrough
-	case predictor.HuggingFace != nil:
-		tags = append(tags, huggingface)
-		fallthrough
-	case predictor.PMML != nil:
-		tags = append(tags, pmml)
-		fallthrough
-	case predictor.LightGBM != nil:
-		tags = append(tags, lightgbm)
-		fallthrough
-	case predictor.Paddle != nil:
-		tags = append(tags, paddle)
-		fallthrough
 	case predictor.Model != nil:
-		modelFormat := predictor.Model.ModelFormat
-		tag := modelFormat.Name
-		if modelFormat.Version != nil {
-			tag = tag + "-" + *modelFormat.Version
+		format := predictor.Model.ModelFormat.Name
+		if predictor.Model.ModelFormat.Version != nil {
+			format = format + "-" + *predictor.Model.ModelFormat.Version
+		}
+		candidates = append(candidates, format)
+		if predictor.Model.Runtime != nil {
+			candidates = append(candidates, "runtime:"+*predictor.Model.Runtime)
 		}
-		tag = strings.ToLower(tag)
-		tags = append(tags, tag)
+	case len(framework) > 0:
+		candidates = append(candidates, framework)
+	}
+	if spec != nil {
+		if spec.StorageURI != nil {
+			if scheme, _, found := strings.Cut(*spec.StorageURI, "://"); found {
+				candidates = append(candidates, "storage:"+scheme)
+			}
+		}
+		if spec.ProtocolVersion != nil {
+			candidates = append(candidates, "protocol:"+string(*spec.ProtocolVersion))
+		}
+	}
+	if pop.requestsGPU() {
+		candidates = append(candidates, "gpu")
+	}
+	if predictor.MinReplicas != nil {
+		candidates = append(candidates, fmt.Sprintf("min-replicas:%d", *predictor.MinReplicas))
+	}
+	if predictor.MaxReplicas > 0 {
+		candidates = append(candidates, fmt.Sprintf("max-replicas:%d", predictor.MaxReplicas))
+	}
+	if mode := pop.deploymentMode(); len(mode) > 0 {
+		candidates = append(candidates, "deployment:"+mode)
 	}
 	explainer := pop.is.Spec.Explainer
 	if explainer != nil && explainer.ART != nil {
-		tags = append(tags, strings.ToLower(string(explainer.ART.Type)))
+		candidates = append(candidates, string(explainer.ART.Type))
+	}
+
+	tags := []string{}
+	for _, candidate := range candidates {
+		if tag := backstage.SanitizeTag(candidate); len(tag) > 0 {
+			tags = append(tags, tag)
+		}
 	}
 	return tags
 }
 
+func (pop *commonPopulator) GetModelFormats() []string {
+	formats := []string{}
+	predictor := pop.is.Spec.Predictor
+	for _, framework := range predictorFrameworks {
+		if framework.spec(&predictor) != nil {
+			formats = append(formats, framework.name)
+		}
+	}
+	if predictor.Model != nil {
+		format := predictor.Model.ModelFormat.Name
+		if predictor.Model.ModelFormat.Version != nil {
+			format = format + " " + *predictor.Model.ModelFormat.Version
+		}
+		formats = append(formats, format)
+	}
+	return formats
+}
+
+func (pop *commonPopulator) GetCustomProperties() map[string]string {
+	properties := map[string]string{}
+	if pop.is.Spec.Predictor.Model != nil {
+		model := pop.is.Spec.Predictor.Model
+		if model.StorageURI != nil {
+			properties["storageUri"] = *model.StorageURI
+		}
+		if model.Runtime != nil {
+			properties["runtime"] = *model.Runtime
+		}
+	}
+	return properties
+}
+
+func (pop *commonPopulator) GetAnnotations() map[string]string {
+	annotations := map[string]string{
+		backstage.SOURCE_ANNOTATION:                    backstage.SOURCE_KSERVE,
+		backstage.KUBERNETES_NAMESPACE_ANNOTATION:      pop.is.Namespace,
+		backstage.KUBERNETES_NAME_ANNOTATION:           pop.is.Name,
+		backstage.KUBERNETES_ID_ANNOTATION:             pop.is.Name,
+		backstage.KUBERNETES_LABEL_SELECTOR_ANNOTATION: backstage.KSERVE_INFERENCE_SERVICE_LABEL + "=" + pop.is.Name,
+		backstage.REGISTERED_MODEL_ID_ANNOTATION:       pop.is.Labels[registeredModelIdLabel],
+		backstage.MODEL_VERSION_ID_ANNOTATION:          pop.is.Labels[modelVersionIdLabel],
+	}
+	predictor := pop.is.Spec.Predictor
+	if predictor.Model != nil {
+		annotations[backstage.MODEL_FORMAT_ANNOTATION] = predictor.Model.ModelFormat.Name
+		if predictor.Model.ModelFormat.Version != nil {
+			annotations[backstage.MODEL_FORMAT_VERSION_ANNOTATION] = *predictor.Model.ModelFormat.Version
+		}
+		if predictor.Model.Runtime != nil {
+			annotations[backstage.SERVING_RUNTIME_ANNOTATION] = *predictor.Model.Runtime
+		}
+	} else if formats := pop.GetModelFormats(); len(formats) > 0 {
+		annotations[backstage.MODEL_FORMAT_ANNOTATION] = formats[0]
+	}
+	if _, spec := pop.predictorExtension(); spec != nil && spec.StorageURI != nil {
+		annotations[backstage.STORAGE_URI_ANNOTATION] = *spec.StorageURI
+	}
+	annotations[backstage.DEPLOYMENT_MODE_ANNOTATION] = pop.deploymentMode()
+	return annotations
+}
+
 func (pop *commonPopulator) GetProvidedAPIs() []string {
 	return []string{fmt.Sprintf("%s_%s", pop.is.Namespace, pop.is.Name)}
 }
 
 type componentPopulator struct {
 	commonPopulator
+	// status adds the readiness, model state, and traffic split of the InferenceService to the Component
+	status bool
+}
+
+func (pop *componentPopulator) GetAnnotations() map[string]string {
+	annotations := pop.commonPopulator.GetAnnotations()
+	if pop.status {
+		annotations = pop.statusAnnotations(annotations)
+	}
+	return annotations
+}
+
+func (pop *componentPopulator) GetLinks() []backstage.EntityLink {
+	links := pop.commonPopulator.GetLinks()
+	if pop.status {
+		links = append(links, pop.statusLinks()...)
+	}
+	return links
 }
 
 func (pop *componentPopulator) GetDependsOn() []string {
-	return []string{fmt.Sprintf("resource:%s_%s", pop.is.Namespace, pop.is.Name), fmt.Sprintf("api:%s_%s", pop.is.Namespace, pop.is.Name)}
+	revisions := pop.modelRevisions()
+	if len(revisions) == 0 {
+		return []string{fmt.Sprintf("resource:%s_%s", pop.is.Namespace, pop.is.Name), fmt.Sprintf("api:%s_%s", pop.is.Namespace, pop.is.Name)}
+	}
+	depends := []string{}
+	for _, revision := range revisions {
+		depends = append(depends, "resource:"+pop.revisionResourceName(revision))
+	}
+	return append(depends, fmt.Sprintf("api:%s_%s", pop.is.Namespace, pop.is.Name))
 }
 
 func (pop *componentPopulator) GetTechdocRef() string {
@@ -183,8 +401,55 @@ func (pop *componentPopulator) GetDisplayName() string {
 	return fmt.Sprintf("The %s model server", pop.GetName())
 }
 
+// GetModelDocURLs points to the Hugging Face model card when the model is pulled from Hugging Face, either through its
+// storage URI or the --model_id argument of the Hugging Face runtime
+func (pop *componentPopulator) GetModelDocURLs() []string {
+	urls := []string{}
+	predictor := pop.is.Spec.Predictor
+	specs := []*serverapiv1beta1.PredictorExtensionSpec{}
+	if predictor.Model != nil {
+		specs = append(specs, &predictor.Model.PredictorExtensionSpec)
+	}
+	if predictor.HuggingFace != nil {
+		specs = append(specs, &predictor.HuggingFace.PredictorExtensionSpec)
+	}
+	for _, spec := range specs {
+		if spec.StorageURI != nil && strings.HasPrefix(*spec.StorageURI, backstage.HUGGINGFACE_STORAGE_PREFIX) {
+			urls = append(urls, backstage.HuggingFaceModelCardURL(*spec.StorageURI))
+			continue
+		}
+		for _, arg := range spec.Args {
+			if strings.HasPrefix(arg, hfModelIdArg) {
+				urls = append(urls, backstage.HuggingFaceModelCardURL(strings.TrimPrefix(arg, hfModelIdArg)))
+			}
+		}
+	}
+	return urls
+}
+
+func (pop *componentPopulator) GetServedModelName() string {
+	return pop.is.Name
+}
+
 type resourcePopulator struct {
 	commonPopulator
+	// revision is set when there is a Resource for each revision of the predictor serving traffic
+	revision *modelRevision
+}
+
+func (pop *resourcePopulator) GetName() string {
+	if pop.revision != nil {
+		return pop.revisionResourceName(*pop.revision)
+	}
+	return pop.commonPopulator.GetName()
+}
+
+func (pop *resourcePopulator) GetAnnotations() map[string]string {
+	annotations := pop.commonPopulator.GetAnnotations()
+	if pop.revision != nil {
+		annotations = revisionAnnotations(annotations, *pop.revision)
+	}
+	return annotations
 }
 
 func (pop *resourcePopulator) GetDependencyOf() []string {
@@ -196,6 +461,9 @@ func (pop *resourcePopulator) GetTechdocRef() string {
 }
 
 func (pop *resourcePopulator) GetDisplayName() string {
+	if pop.revision != nil {
+		return fmt.Sprintf("The %s_%s ai model revision %s", pop.is.Namespace, pop.is.Name, pop.revision.name)
+	}
 	return fmt.Sprintf("The %s ai model", pop.GetName())
 }
 
@@ -209,11 +477,14 @@ func (pop *apiPopulator) GetDependencyOf() []string {
 
 func (pop *apiPopulator) GetDefinition() string {
 	if pop.is.Status.URL == nil {
-		return ""
+		return backstage.API_DEFINITION_PLACEHOLDER
 	}
 	defBytes, _ := util.FetchURL(pop.is.Status.URL.String() + "/openapi.json")
 	dst := bytes.Buffer{}
 	json.Indent(&dst, defBytes, "", "    ")
+	if dst.Len() == 0 {
+		return backstage.API_DEFINITION_PLACEHOLDER
+	}
 	return dst.String()
 }
 
@@ -285,7 +556,7 @@ func NewCmd(cfg *config.Config) *cobra.Command {
 						return err
 					}
 
-					err = callBackstagePrinters(owner, lifecycle, is, cmd)
+					err = callBackstagePrinters(cfg, owner, lifecycle, is, cmd)
 					if err != nil {
 						return err
 					}
@@ -298,7 +569,7 @@ func NewCmd(cfg *config.Config) *cobra.Command {
 					return err
 				}
 				for _, is := range isl.Items {
-					err = callBackstagePrinters(owner, lifecycle, &is, cmd)
+					err = callBackstagePrinters(cfg, owner, lifecycle, &is, cmd)
 					if err != nil {
 						klog.Errorf("%s", err.Error())
 						klog.Flush()
@@ -313,29 +584,55 @@ func NewCmd(cfg *config.Config) *cobra.Command {
 	return cmd
 }
 
-func callBackstagePrinters(owner, lifecycle string, is *serverapiv1beta1.InferenceService, cmd *cobra.Command) error {
+func callBackstagePrinters(cfg *config.Config, owner, lifecycle string, is *serverapiv1beta1.InferenceService, cmd *cobra.Command) error {
+	types := backstage.NewEntityTypes(cfg)
 	compPop := componentPopulator{}
 	compPop.owner = owner
 	compPop.lifecycle = lifecycle
+	compPop.types = types
 	compPop.is = is
-	err := backstage.PrintComponent(&compPop, cmd)
+	compPop.status = cfg.ServingStatus
+
+	out, err := backstage.NewModelOutput(cmd.OutOrStdout(), cfg.OutputDir, compPop.GetName())
 	if err != nil {
 		return err
 	}
+	defer out.Close()
 
-	resPop := resourcePopulator{}
-	resPop.owner = owner
-	resPop.lifecycle = lifecycle
-	resPop.is = is
-	err = backstage.PrintResource(&resPop, cmd)
+	err = backstage.PrintComponent(&compPop, out)
 	if err != nil {
 		return err
 	}
 
+	// a Resource for each revision serving traffic during a rollout, otherwise the one for the InferenceService
+	resPops := []*resourcePopulator{{}}
+	if revisions := compPop.modelRevisions(); len(revisions) > 0 {
+		resPops = []*resourcePopulator{}
+		for _, revision := range revisions {
+			resPops = append(resPops, &resourcePopulator{revision: &revision})
+		}
+	}
+	docPops := []backstage.CommonPopulator{&compPop}
+	for _, resPop := range resPops {
+		resPop.owner = owner
+		resPop.lifecycle = lifecycle
+		resPop.types = types
+		resPop.is = is
+		err = backstage.PrintResource(resPop, out)
+		if err != nil {
+			return err
+		}
+		docPops = append(docPops, resPop)
+	}
+
 	apiPop := apiPopulator{}
 	apiPop.owner = owner
 	apiPop.lifecycle = lifecycle
+	apiPop.types = types
 	apiPop.is = is
-	err = backstage.PrintAPI(&apiPop, cmd)
-	return err
+	err = backstage.PrintAPI(&apiPop, out)
+	if err != nil {
+		return err
+	}
+	return out.WriteTechDocs(cmd.Context(), cfg, append(docPops, &apiPop)...)
 }
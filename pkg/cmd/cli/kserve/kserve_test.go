@@ -2,13 +2,20 @@ package kserve
 
 import (
 	"context"
+	"github.com/gabemontero/backstage-ai-cli/pkg/cmd/cli/backstage"
 	"github.com/gabemontero/backstage-ai-cli/pkg/config"
 	"github.com/gabemontero/backstage-ai-cli/test/stub"
 	serverapiv1beta1 "github.com/kserve/kserve/pkg/apis/serving/v1beta1"
 	fakeservingv1beta1 "github.com/kserve/kserve/pkg/client/clientset/versioned/fake"
+	kserveconstants "github.com/kserve/kserve/pkg/constants"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/apis"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -95,22 +102,22 @@ func TestNewCmd(t *testing.T) {
 				},
 				{
 					ObjectMeta: metav1.ObjectMeta{
-						Namespace: metav1.NamespaceDefault,
-						Name:      "is-2",
+						Namespace:   metav1.NamespaceDefault,
+						Name:        "is-2",
+						Annotations: map[string]string{kserveconstants.DeploymentMode: string(kserveconstants.RawDeployment)},
 					},
 					Spec: serverapiv1beta1.InferenceServiceSpec{
 						Predictor: serverapiv1beta1.PredictorSpec{
-							SKLearn:     &serverapiv1beta1.SKLearnSpec{},
-							XGBoost:     &serverapiv1beta1.XGBoostSpec{},
-							Tensorflow:  &serverapiv1beta1.TFServingSpec{},
-							PyTorch:     &serverapiv1beta1.TorchServeSpec{},
-							Triton:      &serverapiv1beta1.TritonSpec{},
-							ONNX:        &serverapiv1beta1.ONNXRuntimeSpec{},
-							HuggingFace: &serverapiv1beta1.HuggingFaceRuntimeSpec{},
-							PMML:        &serverapiv1beta1.PMMLSpec{},
-							LightGBM:    &serverapiv1beta1.LightGBMSpec{},
-							Paddle:      &serverapiv1beta1.PaddleServerSpec{},
-							Model:       &serverapiv1beta1.ModelSpec{ModelFormat: serverapiv1beta1.ModelFormat{Name: "f1", Version: &version}},
+							ComponentExtensionSpec: serverapiv1beta1.ComponentExtensionSpec{MinReplicas: &minReplicas, MaxReplicas: 3},
+							Model: &serverapiv1beta1.ModelSpec{
+								ModelFormat: serverapiv1beta1.ModelFormat{Name: "f1", Version: &version},
+								Runtime:     &runtime,
+								PredictorExtensionSpec: serverapiv1beta1.PredictorExtensionSpec{
+									StorageURI:      &storageURI,
+									ProtocolVersion: &protocolVersion,
+									Container:       corev1.Container{Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")}}},
+								},
+							},
 						},
 						Explainer: &serverapiv1beta1.ExplainerSpec{
 							ART: &serverapiv1beta1.ARTExplainerSpec{Type: serverapiv1beta1.ARTSquareAttackExplainer},
@@ -196,22 +203,22 @@ func TestNewCmd(t *testing.T) {
 				},
 				{
 					ObjectMeta: metav1.ObjectMeta{
-						Namespace: metav1.NamespaceDefault,
-						Name:      "is-2",
+						Namespace:   metav1.NamespaceDefault,
+						Name:        "is-2",
+						Annotations: map[string]string{kserveconstants.DeploymentMode: string(kserveconstants.RawDeployment)},
 					},
 					Spec: serverapiv1beta1.InferenceServiceSpec{
 						Predictor: serverapiv1beta1.PredictorSpec{
-							SKLearn:     &serverapiv1beta1.SKLearnSpec{},
-							XGBoost:     &serverapiv1beta1.XGBoostSpec{},
-							Tensorflow:  &serverapiv1beta1.TFServingSpec{},
-							PyTorch:     &serverapiv1beta1.TorchServeSpec{},
-							Triton:      &serverapiv1beta1.TritonSpec{},
-							ONNX:        &serverapiv1beta1.ONNXRuntimeSpec{},
-							HuggingFace: &serverapiv1beta1.HuggingFaceRuntimeSpec{},
-							PMML:        &serverapiv1beta1.PMMLSpec{},
-							LightGBM:    &serverapiv1beta1.LightGBMSpec{},
-							Paddle:      &serverapiv1beta1.PaddleServerSpec{},
-							Model:       &serverapiv1beta1.ModelSpec{ModelFormat: serverapiv1beta1.ModelFormat{Name: "f1", Version: &version}},
+							ComponentExtensionSpec: serverapiv1beta1.ComponentExtensionSpec{MinReplicas: &minReplicas, MaxReplicas: 3},
+							Model: &serverapiv1beta1.ModelSpec{
+								ModelFormat: serverapiv1beta1.ModelFormat{Name: "f1", Version: &version},
+								Runtime:     &runtime,
+								PredictorExtensionSpec: serverapiv1beta1.PredictorExtensionSpec{
+									StorageURI:      &storageURI,
+									ProtocolVersion: &protocolVersion,
+									Container:       corev1.Container{Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")}}},
+								},
+							},
 						},
 						Explainer: &serverapiv1beta1.ExplainerSpec{
 							ART: &serverapiv1beta1.ARTExplainerSpec{Type: serverapiv1beta1.ARTSquareAttackExplainer},
@@ -305,6 +312,115 @@ func TestNewCmd(t *testing.T) {
 
 }
 
+func TestNewCmdOutputDir(t *testing.T) {
+	cfg := &config.Config{OutputDir: t.TempDir()}
+	setupConfig(cfg, []serverapiv1beta1.InferenceService{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: metav1.NamespaceDefault,
+				Name:      "is-1",
+			},
+		},
+	})
+	stdout, _, err := stub.ExecuteCommand(NewCmd(cfg), "owner", "lifecycle")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if len(stdout) > 0 {
+		t.Errorf("expected no output on stdout with --output-dir, got %s", stdout)
+	}
+	buf, err := os.ReadFile(filepath.Join(cfg.OutputDir, "default_is-1", "catalog-info.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if !strings.Contains(string(buf), urlNotSet) {
+		t.Errorf("expected catalog-info.yaml to contain %s, got %s", urlNotSet, string(buf))
+	}
+	for _, ref := range []string{"", "resource", "api"} {
+		for _, file := range []string{"mkdocs.yml", filepath.Join("docs", "index.md")} {
+			if _, err = os.Stat(filepath.Join(cfg.OutputDir, "default_is-1", ref, file)); err != nil {
+				t.Errorf("expected TechDocs file: %s", err.Error())
+			}
+		}
+	}
+}
+
+func TestGetAnnotations(t *testing.T) {
+	version, runtime, uri := "1", "kserve-ovms", "s3://models/mnist"
+	pop := &commonPopulator{is: &serverapiv1beta1.InferenceService{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "my-ds-project",
+			Name:        "mnist",
+			Labels:      map[string]string{registeredModelIdLabel: "1", modelVersionIdLabel: "2"},
+			Annotations: map[string]string{kserveconstants.DeploymentMode: string(kserveconstants.ModelMeshDeployment)},
+		},
+		Spec: serverapiv1beta1.InferenceServiceSpec{
+			Predictor: serverapiv1beta1.PredictorSpec{
+				Model: &serverapiv1beta1.ModelSpec{
+					ModelFormat:            serverapiv1beta1.ModelFormat{Name: "onnx", Version: &version},
+					Runtime:                &runtime,
+					PredictorExtensionSpec: serverapiv1beta1.PredictorExtensionSpec{StorageURI: &uri},
+				},
+			},
+		},
+	}}
+	expected := map[string]string{
+		backstage.SOURCE_ANNOTATION:                    backstage.SOURCE_KSERVE,
+		backstage.KUBERNETES_NAMESPACE_ANNOTATION:      "my-ds-project",
+		backstage.KUBERNETES_NAME_ANNOTATION:           "mnist",
+		backstage.KUBERNETES_ID_ANNOTATION:             "mnist",
+		backstage.KUBERNETES_LABEL_SELECTOR_ANNOTATION: "serving.kserve.io/inferenceservice=mnist",
+		backstage.REGISTERED_MODEL_ID_ANNOTATION:       "1",
+		backstage.MODEL_VERSION_ID_ANNOTATION:          "2",
+		backstage.MODEL_FORMAT_ANNOTATION:              "onnx",
+		backstage.MODEL_FORMAT_VERSION_ANNOTATION:      "1",
+		backstage.SERVING_RUNTIME_ANNOTATION:           "kserve-ovms",
+		backstage.STORAGE_URI_ANNOTATION:               "s3://models/mnist",
+		backstage.DEPLOYMENT_MODE_ANNOTATION:           "ModelMesh",
+	}
+	if annotations := pop.GetAnnotations(); !reflect.DeepEqual(expected, annotations) {
+		t.Errorf("expected annotations %v, got %v", expected, annotations)
+	}
+}
+
+func TestGetTags(t *testing.T) {
+	uri := "hf://meta-llama/Llama-3.1-8B"
+	for _, tc := range []struct {
+		name      string
+		predictor serverapiv1beta1.PredictorSpec
+		tags      []string
+	}{
+		{
+			name: "no predictor",
+			tags: []string{},
+		},
+		{
+			name:      "framework predictor",
+			predictor: serverapiv1beta1.PredictorSpec{SKLearn: &serverapiv1beta1.SKLearnSpec{}},
+			tags:      []string{sklearn},
+		},
+		{
+			name: "framework predictor with storage",
+			predictor: serverapiv1beta1.PredictorSpec{HuggingFace: &serverapiv1beta1.HuggingFaceRuntimeSpec{
+				PredictorExtensionSpec: serverapiv1beta1.PredictorExtensionSpec{StorageURI: &uri},
+			}},
+			tags: []string{huggingface, "storage:hf"},
+		},
+		{
+			name: "custom container with a gpu",
+			predictor: serverapiv1beta1.PredictorSpec{PodSpec: serverapiv1beta1.PodSpec{Containers: []corev1.Container{
+				{Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{"amd.com/gpu": resource.MustParse("2")}}},
+			}}},
+			tags: []string{"gpu"},
+		},
+	} {
+		pop := &commonPopulator{is: &serverapiv1beta1.InferenceService{Spec: serverapiv1beta1.InferenceServiceSpec{Predictor: tc.predictor}}}
+		if tags := pop.GetTags(); !reflect.DeepEqual(tc.tags, tags) {
+			t.Errorf("%s: expected tags %v, got %v", tc.name, tc.tags, tags)
+		}
+	}
+}
+
 func testHelpOK(stdout string, cmd *cobra.Command) bool {
 	if strings.Contains(stdout, cmd.Long) {
 		return true
@@ -313,7 +429,11 @@ func testHelpOK(stdout string, cmd *cobra.Command) bool {
 }
 
 var (
-	version = "v1.0"
+	version         = "v1.0"
+	runtime         = "My_Runtime"
+	storageURI      = "pvc://models/f1"
+	protocolVersion = kserveconstants.ProtocolV2
+	minReplicas     = 1
 )
 
 const (
@@ -321,8 +441,15 @@ const (
 kind: Component
 metadata:
   annotations:
-    backstage.io/techdocs-ref: ./
+    ai.backstage.io/kubernetes-name: is-1
+    ai.backstage.io/source: kserve
+    backstage.io/kubernetes-id: is-1
+    backstage.io/kubernetes-label-selector: serving.kserve.io/inferenceservice=is-1
+    backstage.io/kubernetes-namespace: default
+    backstage.io/techdocs-ref: dir:./
   description: KServe instance default:is-1
+  labels:
+    ai.backstage.io/source: kserve
   name: default_is-1
 spec:
   dependsOn:
@@ -340,8 +467,15 @@ apiVersion: backstage.io/v1alpha1
 kind: Resource
 metadata:
   annotations:
-    backstage.io/techdocs-ref: resource/
+    ai.backstage.io/kubernetes-name: is-1
+    ai.backstage.io/source: kserve
+    backstage.io/kubernetes-id: is-1
+    backstage.io/kubernetes-label-selector: serving.kserve.io/inferenceservice=is-1
+    backstage.io/kubernetes-namespace: default
+    backstage.io/techdocs-ref: dir:resource/
   description: KServe instance default:is-1
+  labels:
+    ai.backstage.io/source: kserve
   name: default_is-1
 spec:
   dependencyOf:
@@ -358,11 +492,18 @@ apiVersion: backstage.io/v1alpha1
 kind: API
 metadata:
   annotations:
-    backstage.io/techdocs-ref: api/
+    ai.backstage.io/kubernetes-name: is-1
+    ai.backstage.io/source: kserve
+    backstage.io/kubernetes-id: is-1
+    backstage.io/kubernetes-label-selector: serving.kserve.io/inferenceservice=is-1
+    backstage.io/kubernetes-namespace: default
+    backstage.io/techdocs-ref: dir:api/
   description: KServe instance default:is-1
+  labels:
+    ai.backstage.io/source: kserve
   name: default_is-1
 spec:
-  definition: ""
+  definition: no-definition-yet
   dependencyOf:
   - component:default_is-1
   lifecycle: lifecycle
@@ -375,8 +516,15 @@ spec:
 kind: Component
 metadata:
   annotations:
-    backstage.io/techdocs-ref: ./
+    ai.backstage.io/kubernetes-name: is-1
+    ai.backstage.io/source: kserve
+    backstage.io/kubernetes-id: is-1
+    backstage.io/kubernetes-label-selector: serving.kserve.io/inferenceservice=is-1
+    backstage.io/kubernetes-namespace: default
+    backstage.io/techdocs-ref: dir:./
   description: KServe instance default:is-1
+  labels:
+    ai.backstage.io/source: kserve
   links:
   - icon: WebAsset
     title: API URL
@@ -399,8 +547,15 @@ apiVersion: backstage.io/v1alpha1
 kind: Resource
 metadata:
   annotations:
-    backstage.io/techdocs-ref: resource/
+    ai.backstage.io/kubernetes-name: is-1
+    ai.backstage.io/source: kserve
+    backstage.io/kubernetes-id: is-1
+    backstage.io/kubernetes-label-selector: serving.kserve.io/inferenceservice=is-1
+    backstage.io/kubernetes-namespace: default
+    backstage.io/techdocs-ref: dir:resource/
   description: KServe instance default:is-1
+  labels:
+    ai.backstage.io/source: kserve
   links:
   - icon: WebAsset
     title: API URL
@@ -422,8 +577,15 @@ apiVersion: backstage.io/v1alpha1
 kind: API
 metadata:
   annotations:
-    backstage.io/techdocs-ref: api/
+    ai.backstage.io/kubernetes-name: is-1
+    ai.backstage.io/source: kserve
+    backstage.io/kubernetes-id: is-1
+    backstage.io/kubernetes-label-selector: serving.kserve.io/inferenceservice=is-1
+    backstage.io/kubernetes-namespace: default
+    backstage.io/techdocs-ref: dir:api/
   description: KServe instance default:is-1
+  labels:
+    ai.backstage.io/source: kserve
   links:
   - icon: WebAsset
     title: API URL
@@ -431,7 +593,7 @@ metadata:
     url: https://kserve.com
   name: default_is-1
 spec:
-  definition: ""
+  definition: no-definition-yet
   dependencyOf:
   - component:default_is-1
   lifecycle: lifecycle
@@ -509,17 +671,14 @@ spec:
 `
 	nameTags2 = `  name: default_is-2
   tags:
-  - sklearn
-  - xgboost
-  - tensorflow
-  - pytorch
-  - triton
-  - onnx
-  - huggingface
-  - pmml
-  - lightgbm
-  - paddle
-  - f1-v1.0
+  - f1-v1-0
+  - runtime:my-runtime
+  - storage:pvc
+  - protocol:v2
+  - gpu
+  - min-replicas:1
+  - max-replicas:3
+  - deployment:rawdeployment
   - squareattack
 `
 	compSpec2 = `spec:
@@ -546,7 +705,7 @@ spec:
   type: api-model
 `
 	apiSpec2 = `spec:
-  definition: ""
+  definition: no-definition-yet
   dependencyOf:
   - component:default_is-2
   lifecycle: lifecycle
@@ -0,0 +1,77 @@
+package kserve
+
+import (
+	"fmt"
+	"github.com/gabemontero/backstage-ai-cli/pkg/cmd/cli/backstage"
+	serverapiv1beta1 "github.com/kserve/kserve/pkg/apis/serving/v1beta1"
+	"strconv"
+)
+
+const (
+	latestRevisionRole   = "latest"
+	previousRevisionRole = "previous"
+
+	// knativeRevisionLabel is on the pods Knative runs for a revision
+	knativeRevisionLabel = "serving.knative.dev/revision"
+)
+
+// modelRevision is a revision of the predictor serving part of the traffic during a rollout
+type modelRevision struct {
+	name    string
+	role    string
+	percent int64
+}
+
+// modelRevisions are the latest and previous rolled out revisions of the predictor when the status shows both, as
+// during a canary rollout, with the share of the traffic each receives.  When canaryTrafficPercent is set but only the
+// latest revision has rolled out so far, it is the one revision, and serves all the traffic.  The traffic split in the
+// status wins over canaryTrafficPercent, which is what was asked for rather than what is in effect.
+func (pop *commonPopulator) modelRevisions() []modelRevision {
+	status := pop.is.Status.Components[serverapiv1beta1.PredictorComponent]
+	latest, previous := status.LatestRolledoutRevision, status.PreviousRolledoutRevision
+	if len(latest) == 0 {
+		latest = status.LatestReadyRevision
+	}
+	canary := pop.is.Spec.Predictor.CanaryTrafficPercent
+	if latest == previous {
+		previous = ""
+	}
+	if len(latest) == 0 || (len(previous) == 0 && canary == nil) {
+		return nil
+	}
+
+	latestPercent, previousPercent := int64(100), int64(0)
+	if canary != nil && len(previous) > 0 {
+		latestPercent, previousPercent = *canary, 100-*canary
+	}
+	if split := pop.trafficSplit(); len(split) > 0 {
+		latestPercent, previousPercent = 0, 0
+		for _, traffic := range split {
+			switch traffic.revision {
+			case latest:
+				latestPercent += traffic.percent
+			case previous:
+				previousPercent += traffic.percent
+			}
+		}
+	}
+	revisions := []modelRevision{{name: latest, role: latestRevisionRole, percent: latestPercent}}
+	if len(previous) > 0 {
+		revisions = append(revisions, modelRevision{name: previous, role: previousRevisionRole, percent: previousPercent})
+	}
+	return revisions
+}
+
+func (pop *commonPopulator) revisionResourceName(revision modelRevision) string {
+	return fmt.Sprintf("%s_%s", pop.is.Namespace, revision.name)
+}
+
+// revisionAnnotations adds which revision of the predictor a Resource is and how much of the traffic it serves, and
+// narrows its pods to those of the revision
+func revisionAnnotations(annotations map[string]string, revision modelRevision) map[string]string {
+	annotations[backstage.KUBERNETES_LABEL_SELECTOR_ANNOTATION] = knativeRevisionLabel + "=" + revision.name
+	annotations[backstage.MODEL_REVISION_ANNOTATION] = revision.name
+	annotations[backstage.MODEL_REVISION_ROLE_ANNOTATION] = revision.role
+	annotations[backstage.TRAFFIC_PERCENT_ANNOTATION] = strconv.FormatInt(revision.percent, 10)
+	return annotations
+}
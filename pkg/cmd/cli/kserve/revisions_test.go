@@ -0,0 +1,130 @@
+package kserve
+
+import (
+	"github.com/gabemontero/backstage-ai-cli/pkg/config"
+	"github.com/gabemontero/backstage-ai-cli/test/stub"
+	serverapiv1beta1 "github.com/kserve/kserve/pkg/apis/serving/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	knservingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func canaryInferenceService(canary *int64, traffic []knservingv1.TrafficTarget) *serverapiv1beta1.InferenceService {
+	return &serverapiv1beta1.InferenceService{
+		ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "is-1"},
+		Spec: serverapiv1beta1.InferenceServiceSpec{Predictor: serverapiv1beta1.PredictorSpec{
+			ComponentExtensionSpec: serverapiv1beta1.ComponentExtensionSpec{CanaryTrafficPercent: canary},
+		}},
+		Status: serverapiv1beta1.InferenceServiceStatus{
+			Components: map[serverapiv1beta1.ComponentType]serverapiv1beta1.ComponentStatusSpec{
+				serverapiv1beta1.PredictorComponent: {
+					LatestRolledoutRevision:   "is-1-predictor-00002",
+					PreviousRolledoutRevision: "is-1-predictor-00001",
+					Traffic:                   traffic,
+				},
+			},
+		},
+	}
+}
+
+func TestModelRevisions(t *testing.T) {
+	canary, latest, previous := int64(20), int64(30), int64(70)
+	for _, tc := range []struct {
+		name      string
+		is        *serverapiv1beta1.InferenceService
+		revisions []modelRevision
+	}{
+		{
+			name: "no rollout",
+			is:   &serverapiv1beta1.InferenceService{},
+		},
+		{
+			name: "same revision",
+			is: &serverapiv1beta1.InferenceService{Status: serverapiv1beta1.InferenceServiceStatus{
+				Components: map[serverapiv1beta1.ComponentType]serverapiv1beta1.ComponentStatusSpec{
+					serverapiv1beta1.PredictorComponent: {LatestRolledoutRevision: "is-1-predictor-00001", PreviousRolledoutRevision: "is-1-predictor-00001"},
+				},
+			}},
+		},
+		{
+			name: "canary traffic percent",
+			is:   canaryInferenceService(&canary, nil),
+			revisions: []modelRevision{
+				{name: "is-1-predictor-00002", role: latestRevisionRole, percent: 20},
+				{name: "is-1-predictor-00001", role: previousRevisionRole, percent: 80},
+			},
+		},
+		{
+			name: "canary with only the latest revision rolled out",
+			is: &serverapiv1beta1.InferenceService{
+				Spec: serverapiv1beta1.InferenceServiceSpec{Predictor: serverapiv1beta1.PredictorSpec{
+					ComponentExtensionSpec: serverapiv1beta1.ComponentExtensionSpec{CanaryTrafficPercent: &canary},
+				}},
+				Status: serverapiv1beta1.InferenceServiceStatus{
+					Components: map[serverapiv1beta1.ComponentType]serverapiv1beta1.ComponentStatusSpec{
+						serverapiv1beta1.PredictorComponent: {LatestRolledoutRevision: "is-1-predictor-00001"},
+					},
+				},
+			},
+			revisions: []modelRevision{
+				{name: "is-1-predictor-00001", role: latestRevisionRole, percent: 100},
+			},
+		},
+		{
+			name: "traffic split in status",
+			is: canaryInferenceService(&canary, []knservingv1.TrafficTarget{
+				{RevisionName: "is-1-predictor-00002", Percent: &latest},
+				{RevisionName: "is-1-predictor-00001", Percent: &previous},
+			}),
+			revisions: []modelRevision{
+				{name: "is-1-predictor-00002", role: latestRevisionRole, percent: 30},
+				{name: "is-1-predictor-00001", role: previousRevisionRole, percent: 70},
+			},
+		},
+		{
+			name: "rolled out without canary",
+			is:   canaryInferenceService(nil, nil),
+			revisions: []modelRevision{
+				{name: "is-1-predictor-00002", role: latestRevisionRole, percent: 100},
+				{name: "is-1-predictor-00001", role: previousRevisionRole, percent: 0},
+			},
+		},
+	} {
+		pop := &commonPopulator{is: tc.is}
+		if revisions := pop.modelRevisions(); !reflect.DeepEqual(tc.revisions, revisions) {
+			t.Errorf("%s: expected revisions %v, got %v", tc.name, tc.revisions, revisions)
+		}
+	}
+}
+
+func TestNewCmdCanary(t *testing.T) {
+	canary := int64(20)
+	cfg := &config.Config{}
+	setupConfig(cfg, []serverapiv1beta1.InferenceService{*canaryInferenceService(&canary, nil)})
+	stdout, _, err := stub.ExecuteCommand(NewCmd(cfg), "owner", "lifecycle")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	entities := strings.Split(strings.TrimSuffix(stdout, "---\n"), "---")
+	if len(entities) != 4 {
+		t.Fatalf("expected a Component, two Resources, and an API, got %s", stdout)
+	}
+	for _, expected := range []string{"- resource:default_is-1-predictor-00002", "- resource:default_is-1-predictor-00001", "- api:default_is-1"} {
+		if !strings.Contains(entities[0], expected) {
+			t.Errorf("expected the component to contain %s, got %s", expected, entities[0])
+		}
+	}
+	for i, expected := range [][]string{
+		{"name: default_is-1-predictor-00002", "ai.backstage.io/model-revision-role: latest", "ai.backstage.io/traffic-percent: \"20\"", "backstage.io/kubernetes-label-selector: serving.knative.dev/revision=is-1-predictor-00002"},
+		{"name: default_is-1-predictor-00001", "ai.backstage.io/model-revision-role: previous", "ai.backstage.io/traffic-percent: \"80\""},
+	} {
+		resource := entities[i+1]
+		for _, str := range append(expected, "kind: Resource", "- component:default_is-1") {
+			if !strings.Contains(resource, str) {
+				t.Errorf("expected resource %d to contain %s, got %s", i, str, resource)
+			}
+		}
+	}
+}
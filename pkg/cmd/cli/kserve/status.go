@@ -0,0 +1,137 @@
+package kserve
+
+import (
+	"fmt"
+	"github.com/gabemontero/backstage-ai-cli/pkg/cmd/cli/backstage"
+	serverapiv1beta1 "github.com/kserve/kserve/pkg/apis/serving/v1beta1"
+	"knative.dev/pkg/apis"
+	"strings"
+)
+
+// statusConditions are the InferenceService conditions reported with --status, along with the annotation for each
+var statusConditions = []struct {
+	condition  apis.ConditionType
+	annotation string
+}{
+	{apis.ConditionReady, backstage.READY_ANNOTATION},
+	{serverapiv1beta1.PredictorReady, backstage.PREDICTOR_READY_ANNOTATION},
+	{serverapiv1beta1.IngressReady, backstage.INGRESS_READY_ANNOTATION},
+}
+
+// revisionTraffic is the share of the requests a revision of the predictor receives
+type revisionTraffic struct {
+	revision string
+	percent  int64
+	latest   bool
+	url      *apis.URL
+}
+
+// trafficSplit is the traffic of the predictor across its revisions, as reported in the status
+func (pop *commonPopulator) trafficSplit() []revisionTraffic {
+	split := []revisionTraffic{}
+	for _, target := range pop.is.Status.Components[serverapiv1beta1.PredictorComponent].Traffic {
+		if target.Percent == nil || len(target.RevisionName) == 0 {
+			continue
+		}
+		split = append(split, revisionTraffic{
+			revision: target.RevisionName,
+			percent:  *target.Percent,
+			latest:   target.LatestRevision != nil && *target.LatestRevision,
+			url:      target.URL,
+		})
+	}
+	return split
+}
+
+func (pop *commonPopulator) lastFailure() string {
+	failure := pop.is.Status.ModelStatus.LastFailureInfo
+	if failure == nil {
+		return ""
+	}
+	if len(failure.Reason) == 0 {
+		return failure.Message
+	}
+	if len(failure.Message) == 0 {
+		return string(failure.Reason)
+	}
+	return fmt.Sprintf("%s: %s", failure.Reason, failure.Message)
+}
+
+// statusAnnotations reports the readiness, model state, and traffic split of the InferenceService
+func (pop *commonPopulator) statusAnnotations(annotations map[string]string) map[string]string {
+	for _, c := range statusConditions {
+		if condition := pop.is.Status.GetCondition(c.condition); condition != nil {
+			annotations[c.annotation] = string(condition.Status)
+		}
+	}
+	modelStatus := pop.is.Status.ModelStatus
+	annotations[backstage.MODEL_TRANSITION_STATUS_ANNOTATION] = string(modelStatus.TransitionStatus)
+	if modelStatus.ModelRevisionStates != nil {
+		annotations[backstage.MODEL_STATE_ANNOTATION] = string(modelStatus.ModelRevisionStates.ActiveModelState)
+		annotations[backstage.MODEL_TARGET_STATE_ANNOTATION] = string(modelStatus.ModelRevisionStates.TargetModelState)
+	}
+	annotations[backstage.MODEL_LAST_FAILURE_ANNOTATION] = pop.lastFailure()
+	split := []string{}
+	for _, traffic := range pop.trafficSplit() {
+		split = append(split, fmt.Sprintf("%s=%d", traffic.revision, traffic.percent))
+	}
+	annotations[backstage.TRAFFIC_ANNOTATION] = strings.Join(split, ",")
+	return annotations
+}
+
+// statusLinks are the URLs of the revisions receiving traffic, which KServe sets for tagged revisions
+func (pop *commonPopulator) statusLinks() []backstage.EntityLink {
+	links := []backstage.EntityLink{}
+	for _, traffic := range pop.trafficSplit() {
+		if traffic.url == nil {
+			continue
+		}
+		links = append(links, backstage.EntityLink{
+			URL:   traffic.url.String(),
+			Title: fmt.Sprintf("predictor revision %s (%d%% of traffic)", traffic.revision, traffic.percent),
+			Icon:  backstage.LINK_ICON_WEBASSET,
+			Type:  backstage.LINK_TYPE_WEBSITE,
+		})
+	}
+	return links
+}
+
+// GetHealth is the status of the InferenceService for the health section of the TechDocs, when --status is set
+func (pop *componentPopulator) GetHealth() []backstage.HealthCheck {
+	checks := []backstage.HealthCheck{}
+	if !pop.status {
+		return checks
+	}
+	for _, c := range statusConditions {
+		condition := pop.is.Status.GetCondition(c.condition)
+		if condition == nil {
+			continue
+		}
+		message := condition.Message
+		if len(condition.Reason) > 0 && len(message) > 0 {
+			message = condition.Reason + ": " + message
+		} else if len(condition.Reason) > 0 {
+			message = condition.Reason
+		}
+		checks = append(checks, backstage.HealthCheck{Name: string(c.condition), Status: string(condition.Status), Message: message})
+	}
+	modelStatus := pop.is.Status.ModelStatus
+	if modelStatus.ModelRevisionStates != nil {
+		checks = append(checks, backstage.HealthCheck{
+			Name:    "Model",
+			Status:  string(modelStatus.ModelRevisionStates.ActiveModelState),
+			Message: string(modelStatus.TransitionStatus),
+		})
+	}
+	if failure := pop.lastFailure(); len(failure) > 0 {
+		checks = append(checks, backstage.HealthCheck{Name: "Last failure", Status: "Failed", Message: failure})
+	}
+	for _, traffic := range pop.trafficSplit() {
+		message := ""
+		if traffic.latest {
+			message = "latest revision"
+		}
+		checks = append(checks, backstage.HealthCheck{Name: "Traffic to " + traffic.revision, Status: fmt.Sprintf("%d%%", traffic.percent), Message: message})
+	}
+	return checks
+}
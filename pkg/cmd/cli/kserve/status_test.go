@@ -0,0 +1,110 @@
+package kserve
+
+import (
+	"github.com/gabemontero/backstage-ai-cli/pkg/config"
+	"github.com/gabemontero/backstage-ai-cli/test/stub"
+	serverapiv1beta1 "github.com/kserve/kserve/pkg/apis/serving/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	knservingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func statusInferenceService() serverapiv1beta1.InferenceService {
+	latest, previous := int64(90), int64(10)
+	isLatest := true
+	return serverapiv1beta1.InferenceService{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+			Name:      "is-1",
+		},
+		Status: serverapiv1beta1.InferenceServiceStatus{
+			Status: duckv1.Status{Conditions: duckv1.Conditions{
+				{Type: apis.ConditionReady, Status: corev1.ConditionFalse, Reason: "RevisionFailed", Message: "model failed to load"},
+				{Type: serverapiv1beta1.PredictorReady, Status: corev1.ConditionFalse},
+				{Type: serverapiv1beta1.IngressReady, Status: corev1.ConditionTrue},
+			}},
+			ModelStatus: serverapiv1beta1.ModelStatus{
+				TransitionStatus:    serverapiv1beta1.BlockedByFailedLoad,
+				ModelRevisionStates: &serverapiv1beta1.ModelRevisionStates{ActiveModelState: serverapiv1beta1.FailedToLoad, TargetModelState: serverapiv1beta1.Loaded},
+				LastFailureInfo:     &serverapiv1beta1.FailureInfo{Reason: serverapiv1beta1.ModelLoadFailed, Message: "out of memory"},
+			},
+			Components: map[serverapiv1beta1.ComponentType]serverapiv1beta1.ComponentStatusSpec{
+				serverapiv1beta1.PredictorComponent: {
+					Traffic: []knservingv1.TrafficTarget{
+						{RevisionName: "is-1-predictor-00002", Percent: &latest, LatestRevision: &isLatest, URL: &apis.URL{Scheme: "https", Host: "latest-is-1.kserve.com"}},
+						{RevisionName: "is-1-predictor-00001", Percent: &previous},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestNewCmdStatus(t *testing.T) {
+	cfg := &config.Config{OutputDir: t.TempDir(), ServingStatus: true}
+	setupConfig(cfg, []serverapiv1beta1.InferenceService{statusInferenceService()})
+	_, _, err := stub.ExecuteCommand(NewCmd(cfg), "owner", "lifecycle")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	buf, err := os.ReadFile(filepath.Join(cfg.OutputDir, "default_is-1", "catalog-info.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	component := strings.Split(string(buf), "---")[0]
+	for _, expected := range []string{
+		"ai.backstage.io/ready: \"False\"",
+		"ai.backstage.io/predictor-ready: \"False\"",
+		"ai.backstage.io/ingress-ready: \"True\"",
+		"ai.backstage.io/model-state: FailedToLoad",
+		"ai.backstage.io/model-target-state: Loaded",
+		"ai.backstage.io/model-transition-status: BlockedByFailedLoad",
+		"ai.backstage.io/model-last-failure: 'ModelLoadFailed: out of memory'",
+		"ai.backstage.io/traffic: is-1-predictor-00002=90,is-1-predictor-00001=10",
+		"title: predictor revision is-1-predictor-00002 (90% of traffic)",
+		"url: https://latest-is-1.kserve.com",
+	} {
+		if !strings.Contains(component, expected) {
+			t.Errorf("expected the component to contain %s, got %s", expected, component)
+		}
+	}
+	if strings.Contains(strings.Join(strings.Split(string(buf), "---")[1:], "---"), "ai.backstage.io/ready") {
+		t.Errorf("expected the status only on the component, got %s", string(buf))
+	}
+
+	buf, err = os.ReadFile(filepath.Join(cfg.OutputDir, "default_is-1", "docs", "index.md"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	for _, expected := range []string{
+		"## Health",
+		"| Ready | False | RevisionFailed: model failed to load |",
+		"| IngressReady | True |  |",
+		"| Model | FailedToLoad | BlockedByFailedLoad |",
+		"| Last failure | Failed | ModelLoadFailed: out of memory |",
+		"| Traffic to is-1-predictor-00002 | 90% | latest revision |",
+		"| Traffic to is-1-predictor-00001 | 10% |  |",
+	} {
+		if !strings.Contains(string(buf), expected) {
+			t.Errorf("expected the TechDocs to contain %s, got %s", expected, string(buf))
+		}
+	}
+}
+
+func TestNewCmdWithoutStatus(t *testing.T) {
+	cfg := &config.Config{}
+	setupConfig(cfg, []serverapiv1beta1.InferenceService{statusInferenceService()})
+	stdout, _, err := stub.ExecuteCommand(NewCmd(cfg), "owner", "lifecycle")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	if strings.Contains(stdout, "ai.backstage.io/ready") || strings.Contains(stdout, "predictor revision") {
+		t.Errorf("expected no status without --status, got %s", stdout)
+	}
+}
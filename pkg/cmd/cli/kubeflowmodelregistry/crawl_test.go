@@ -0,0 +1,88 @@
+package kubeflowmodelregistry
+
+import (
+	"context"
+	"fmt"
+	"github.com/gabemontero/backstage-ai-cli/pkg/config"
+	"github.com/gabemontero/backstage-ai-cli/test/stub"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCrawlRegisteredModelsOrder(t *testing.T) {
+	ts := CreateTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/versions"):
+			_, _ = w.Write([]byte(TestJSONStringModelVersionOneLine))
+		case strings.HasSuffix(r.URL.Path, "/artifacts"):
+			_, _ = w.Write([]byte(TestJSONStringModelArtifactOneLine))
+		case strings.Contains(r.URL.Path, LIST_REG_MODEL_URI+"/"):
+			// the lower the id the slower the response, so the requests finish in the reverse order they are made
+			id, _ := strconv.Atoi(r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+			time.Sleep(time.Duration(20-id) * time.Millisecond)
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"id":"%d","name":"model-%d"}`, id, id)))
+		}
+	})
+	defer ts.Close()
+
+	cfg := &config.Config{}
+	SetupKubeflowTestRESTClient(ts, cfg)
+	kfmr := SetupKubeflowRESTClient(cfg)
+
+	models := []*registeredModelData{}
+	for i := 0; i < 20; i++ {
+		models = append(models, &registeredModelData{id: strconv.Itoa(i)})
+	}
+	AssertError(t, crawlRegisteredModels(context.Background(), kfmr, 8, models))
+	for i, model := range models {
+		AssertEqual(t, fmt.Sprintf("model-%d", i), model.rm.Name)
+		AssertEqual(t, 1, len(model.mvs))
+		AssertEqual(t, 1, len(model.mas["2"]))
+	}
+}
+
+func TestCrawlRegisteredModelsCancel(t *testing.T) {
+	ts := CreateTestServer(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	defer ts.Close()
+
+	cfg := &config.Config{RequestTimeout: 50 * time.Millisecond}
+	SetupKubeflowTestRESTClient(ts, cfg)
+	kfmr := SetupKubeflowRESTClient(cfg)
+
+	models := []*registeredModelData{{id: "1"}, {id: "2"}, {id: "3"}}
+	err := crawlRegisteredModels(context.Background(), kfmr, 2, models)
+	if err == nil || !strings.Contains(err.Error(), "deadline exceeded") {
+		t.Errorf("expected a request timeout but got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = crawlRegisteredModels(ctx, kfmr, 2, models)
+	if err != context.Canceled {
+		t.Errorf("expected the crawl to be canceled but got %v", err)
+	}
+}
+
+func TestNewCmdConcurrency(t *testing.T) {
+	ts := CreateGetServer(t)
+	defer ts.Close()
+
+	outputs := []string{}
+	for _, concurrency := range []int{1, 4, 16} {
+		cfg := &config.Config{Concurrency: concurrency}
+		SetupKubeflowTestRESTClient(ts, cfg)
+		_, stdout, _, err := stub.ExecuteCommandC(NewCmd(cfg), "owner", "lifecycle", "1", "1", "1", "1", "1", "1")
+		AssertError(t, err)
+		outputs = append(outputs, stdout)
+	}
+	AssertLineCompare(t, outputs[0], listOutput, 0)
+	for _, stdout := range outputs[1:] {
+		AssertEqual(t, outputs[0], stdout)
+	}
+}
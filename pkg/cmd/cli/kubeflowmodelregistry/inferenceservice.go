@@ -0,0 +1,42 @@
+package kubeflowmodelregistry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/kubeflow/model-registry/pkg/openapi"
+)
+
+func (k *KubeFlowRESTClientWrapper) ListInferenceServices(ctx context.Context) ([]openapi.InferenceService, error) {
+	iss := []openapi.InferenceService{}
+	err := k.listAllPages(ctx, k.RootURL+LIST_INFERENCE_SERVICES_URI, func(buf []byte) (string, error) {
+		list := openapi.InferenceServiceList{}
+		err := json.Unmarshal(buf, &list)
+		if err != nil {
+			return "", err
+		}
+		iss = append(iss, list.Items...)
+		return list.NextPageToken, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return iss, nil
+}
+
+func (k *KubeFlowRESTClientWrapper) ListServeModels(ctx context.Context, id string) ([]openapi.ServeModel, error) {
+	sms := []openapi.ServeModel{}
+	err := k.listAllPages(ctx, k.RootURL+fmt.Sprintf(LIST_SERVE_MODELS_OFF_INFERENCE_SERVICES_URI, id), func(buf []byte) (string, error) {
+		list := openapi.ServeModelList{}
+		err := json.Unmarshal(buf, &list)
+		if err != nil {
+			return "", err
+		}
+		sms = append(sms, list.Items...)
+		return list.NextPageToken, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sms, nil
+}
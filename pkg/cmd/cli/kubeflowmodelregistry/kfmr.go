@@ -1,13 +1,18 @@
 package kubeflowmodelregistry
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/gabemontero/backstage-ai-cli/pkg/cmd/cli/backstage"
 	"github.com/gabemontero/backstage-ai-cli/pkg/config"
 	"github.com/gabemontero/backstage-ai-cli/pkg/util"
 	"github.com/kubeflow/model-registry/pkg/openapi"
 	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
 	"k8s.io/klog/v2"
+	"slices"
 	"strings"
 )
 
@@ -15,7 +20,8 @@ const (
 	kubeflowExample = `
 # Both owner and lifecycle are required parameters.  Examine Backstage Catalog documentation for details.
 # This will query all the RegisteredModel, ModelVersion, and ModelArtifact instances in the Kubeflow Model Registry and build Catalog Component, Resource, and
-# API Entities from the data.
+# API Entities from the data.  Each ServingEnvironment becomes a Catalog System, and each InferenceService of a RegisteredModel
+# becomes an API provided by its Component, with the endpoint taken from the 'url' custom property of the InferenceService.
 $ %s new-model kubeflow <owner> <lifecycle> <args...>
 
 # This will set the URL, Token, and Skip TLS when accessing Kubeflow
@@ -24,6 +30,25 @@ $ %s new-model kubeflow <owner> <lifecycle> --model-metadata-url=https://my-kube
 # This form will pull in only the RegisteredModels with the specified IDs '1' and '2' and their ModelVersion and ModelArtifact
 # children in order to build Catalog Component, Resource, and API Entities.
 $ %s new-model kubeflow <owner> <lifecycle> 1 2 
+
+# This will pull in only the LIVE versions of the RegisteredModels named 'model-1' and 'model-2'
+$ %s new-model kubeflow <owner> <lifecycle> --name=model-1 --name=model-2 --state=LIVE
+
+# This will page through the registered models 50 at a time, most recently updated first
+$ %s new-model kubeflow <owner> <lifecycle> --page-size=50 --order-by=LAST_UPDATE_TIME --sort-order=DESC
+
+# This will fetch up to 16 registered models from the Kubeflow Model Registry at a time, giving up on any single request
+# after 10 seconds rather than the --timeout used for Backstage.  The entities are still printed in the order the registry returns the models.
+$ %s new-model kubeflow <owner> <lifecycle> --concurrency=16 --request-timeout=10s
+
+# Custom properties without a value become tags, URLs become links, and the rest annotations.  This will instead make the
+# 'team' property a label, the 'dataset' property a link, and drop the properties whose names start with 'internal'.
+$ %s new-model kubeflow <owner> <lifecycle> --property-rule=team=label:example.com/team --property-rule='dataset=link:Training dataset' --property-rule='internal*=ignore'
+
+# Resources are named '<registered model>-<model version>', the APIs of model artifacts '<registered model>-<model version>-<artifact>',
+# and the APIs of inference services '<serving environment>-<registered model>-<inference service>'.
+# This will put the entities from the registry in the 'team-a' catalog namespace, so they do not collide with those of other registries.
+$ %s new-model kubeflow <owner> <lifecycle> --catalog-namespace=team-a
 `
 )
 
@@ -50,50 +75,96 @@ func NewCmd(cfg *config.Config) *cobra.Command {
 				ids = args[2:]
 			}
 
+			err := validateListOptions(cfg)
+			if err != nil {
+				klog.Errorf("%s", err.Error())
+				klog.Flush()
+				return err
+			}
+			_, err = parsePropertyRules(cfg.PropertyRules)
+			if err != nil {
+				klog.Errorf("%s", err.Error())
+				klog.Flush()
+				return err
+			}
+			if len(cfg.CatalogNamespace) > 0 {
+				if err = backstage.ValidateNamespace(cfg.CatalogNamespace); err != nil {
+					err = fmt.Errorf("invalid catalog namespace: %s", err.Error())
+					klog.Errorf("%s", err.Error())
+					klog.Flush()
+					return err
+				}
+			}
+
 			kfmr := SetupKubeflowRESTClient(cfg)
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
 
-			if len(ids) == 0 {
-				var err error
+			serving, err := callKubeflowServingREST(ctx, kfmr, cfg.Concurrency)
+			if err != nil {
+				klog.Errorf("%s", err.Error())
+				klog.Flush()
+				return err
+			}
+
+			models := []*registeredModelData{}
+			if len(ids) == 0 && len(cfg.ModelNames) == 0 {
 				var rms []openapi.RegisteredModel
-				rms, err = kfmr.ListRegisteredModels()
+				rms, err = kfmr.ListRegisteredModels(ctx)
 				if err != nil {
 					klog.Errorf("list registered models error: %s", err.Error())
 					klog.Flush()
 					return err
 				}
 				for _, rm := range rms {
-					var mvs []openapi.ModelVersion
-					var mas map[string][]openapi.ModelArtifact
-					mvs, mas, err = callKubeflowREST(*rm.Id, kfmr)
-					if err != nil {
-						klog.Errorf("%s", err.Error())
-						klog.Flush()
-						return err
-					}
-					err = callBackstagePrinters(owner, lifecycle, &rm, mvs, mas, cmd)
-					if err != nil {
-						klog.Errorf("print model catalog: %s", err.Error())
-						klog.Flush()
-						return err
+					// skip the filtered out models before spending any requests on their versions
+					if matchesState(cfg.ModelState, string(rm.GetState())) {
+						models = append(models, &registeredModelData{id: rm.GetId(), rm: &rm})
 					}
 				}
 			} else {
 				for _, id := range ids {
-					rm, err := kfmr.GetRegisteredModel(id)
-					if err != nil {
-						klog.Errorf("get registered model error for %s: %s", id, err.Error())
-						klog.Flush()
-						return err
-					}
-					var mvs []openapi.ModelVersion
-					var mas map[string][]openapi.ModelArtifact
-					mvs, mas, err = callKubeflowREST(*rm.Id, kfmr)
-					if err != nil {
-						klog.Errorf("get model version/artifact error for %s: %s", id, err.Error())
-						klog.Flush()
-						return err
+					models = append(models, &registeredModelData{id: id})
+				}
+				for _, name := range cfg.ModelNames {
+					models = append(models, &registeredModelData{name: name})
+				}
+			}
+
+			err = crawlRegisteredModels(ctx, kfmr, cfg.Concurrency, models)
+			if err != nil {
+				klog.Errorf("%s", err.Error())
+				klog.Flush()
+				return err
+			}
+			selected := []*registeredModelData{}
+			rmIDs := []string{}
+			for _, model := range models {
+				if matchesState(cfg.ModelState, string(model.rm.GetState())) {
+					selected = append(selected, model)
+					rmIDs = append(rmIDs, model.rm.GetId())
+				}
+			}
+			err = printSystems(cfg, owner, lifecycle, serving.environmentsFor(rmIDs), cmd)
+			if err != nil {
+				klog.Errorf("print serving environment catalog: %s", err.Error())
+				klog.Flush()
+				return err
+			}
+			for _, model := range selected {
+				mvs := []openapi.ModelVersion{}
+				for _, mv := range model.mvs {
+					if matchesState(cfg.ModelState, string(mv.GetState())) {
+						mvs = append(mvs, mv)
 					}
-					err = callBackstagePrinters(owner, lifecycle, rm, mvs, mas, cmd)
+				}
+				err = callBackstagePrinters(cfg, owner, lifecycle, model.rm, mvs, model.mas, serving, cmd)
+				if err != nil {
+					klog.Errorf("print model catalog: %s", err.Error())
+					klog.Flush()
+					return err
 				}
 			}
 			return nil
@@ -103,8 +174,83 @@ func NewCmd(cfg *config.Config) *cobra.Command {
 	return cmd
 }
 
-func callKubeflowREST(id string, kfmr *KubeFlowRESTClientWrapper) (mvs []openapi.ModelVersion, ma map[string][]openapi.ModelArtifact, err error) {
-	mvs, err = kfmr.ListModelVersions(id)
+// validateListOptions checks the paging and filtering settings against the values the registry accepts
+func validateListOptions(cfg *config.Config) error {
+	if len(cfg.OrderBy) > 0 {
+		if _, err := openapi.NewOrderByFieldFromValue(cfg.OrderBy); err != nil {
+			return err
+		}
+	}
+	if len(cfg.SortOrder) > 0 {
+		if _, err := openapi.NewSortOrderFromValue(cfg.SortOrder); err != nil {
+			return err
+		}
+	}
+	if len(cfg.ModelState) > 0 {
+		if _, err := openapi.NewRegisteredModelStateFromValue(cfg.ModelState); err != nil {
+			return err
+		}
+	}
+	if cfg.PageSize < 0 {
+		return fmt.Errorf("invalid page size %d: it cannot be negative", cfg.PageSize)
+	}
+	return nil
+}
+
+func matchesState(filter, state string) bool {
+	return len(filter) == 0 || filter == state
+}
+
+// registeredModelData is what the crawl of the registry collects for a registered model; rm is fetched by id or name
+// when not already known from the list of registered models
+type registeredModelData struct {
+	id   string
+	name string
+	rm   *openapi.RegisteredModel
+	mvs  []openapi.ModelVersion
+	mas  map[string][]openapi.ModelArtifact
+}
+
+// crawlRegisteredModels fetches the registered models along with their versions and artifacts with at most concurrency
+// of them in flight, stopping the rest on the first error.  The results are filled in place so the caller can print
+// them in the original order regardless of which requests finish first.
+func crawlRegisteredModels(ctx context.Context, kfmr *KubeFlowRESTClientWrapper, concurrency int, models []*registeredModelData) error {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(concurrency, 1))
+	for _, model := range models {
+		g.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			var err error
+			switch {
+			case model.rm != nil:
+			case len(model.name) > 0:
+				model.rm, err = kfmr.FindRegisteredModel(ctx, model.name)
+				if err != nil {
+					klog.Errorf("find registered model error for %s: %s", model.name, err.Error())
+					return err
+				}
+				model.id = model.rm.GetId()
+			default:
+				model.rm, err = kfmr.GetRegisteredModel(ctx, model.id)
+				if err != nil {
+					klog.Errorf("get registered model error for %s: %s", model.id, err.Error())
+					return err
+				}
+			}
+			model.mvs, model.mas, err = callKubeflowREST(ctx, model.rm.GetId(), kfmr)
+			if err != nil {
+				klog.Errorf("get model version/artifact error for %s: %s", model.id, err.Error())
+			}
+			return err
+		})
+	}
+	return g.Wait()
+}
+
+func callKubeflowREST(ctx context.Context, id string, kfmr *KubeFlowRESTClientWrapper) (mvs []openapi.ModelVersion, ma map[string][]openapi.ModelArtifact, err error) {
+	mvs, err = kfmr.ListModelVersions(ctx, id)
 	if err != nil {
 		klog.Errorf("ERROR: error list model versions for %s: %s", id, err.Error())
 		return
@@ -112,13 +258,13 @@ func callKubeflowREST(id string, kfmr *KubeFlowRESTClientWrapper) (mvs []openapi
 	ma = map[string][]openapi.ModelArtifact{}
 	for _, mv := range mvs {
 		var v []openapi.ModelArtifact
-		v, err = kfmr.ListModelArtifacts(*mv.Id)
+		v, err = kfmr.ListModelArtifacts(ctx, *mv.Id)
 		if err != nil {
 			klog.Errorf("ERROR error list model artifacts for %s:%s: %s", id, *mv.Id, err.Error())
 			return
 		}
 		if len(v) == 0 {
-			v, err = kfmr.ListModelArtifacts(id)
+			v, err = kfmr.ListModelArtifacts(ctx, id)
 			if err != nil {
 				klog.Errorf("ERROR error list model artifacts for %s:%s: %s", id, *mv.Id, err.Error())
 				return
@@ -129,50 +275,306 @@ func callKubeflowREST(id string, kfmr *KubeFlowRESTClientWrapper) (mvs []openapi
 	return
 }
 
-func callBackstagePrinters(owner, lifecycle string, rm *openapi.RegisteredModel, mvs []openapi.ModelVersion, mas map[string][]openapi.ModelArtifact, cmd *cobra.Command) error {
+// servingInfo holds the serving environments, the inference services deployed in them, and the model versions those
+// inference services serve, which are shared across the registered models
+type servingInfo struct {
+	environments      []openapi.ServingEnvironment
+	inferenceServices []openapi.InferenceService
+	serveModels       map[string][]openapi.ServeModel
+}
+
+func callKubeflowServingREST(ctx context.Context, kfmr *KubeFlowRESTClientWrapper, concurrency int) (*servingInfo, error) {
+	var err error
+	serving := &servingInfo{serveModels: map[string][]openapi.ServeModel{}}
+	serving.environments, err = kfmr.ListServingEnvironments(ctx)
+	if err != nil {
+		klog.Errorf("ERROR: error list serving environments: %s", err.Error())
+		return nil, err
+	}
+	serving.inferenceServices, err = kfmr.ListInferenceServices(ctx)
+	if err != nil {
+		klog.Errorf("ERROR: error list inference services: %s", err.Error())
+		return nil, err
+	}
+
+	serveModels := make([][]openapi.ServeModel, len(serving.inferenceServices))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(concurrency, 1))
+	for i, is := range serving.inferenceServices {
+		g.Go(func() error {
+			sms, err := kfmr.ListServeModels(gctx, is.GetId())
+			if err != nil {
+				klog.Errorf("ERROR: error list serve models for %s: %s", is.GetId(), err.Error())
+				return err
+			}
+			serveModels[i] = sms
+			return nil
+		})
+	}
+	if err = g.Wait(); err != nil {
+		return nil, err
+	}
+	for i, is := range serving.inferenceServices {
+		serving.serveModels[is.GetId()] = serveModels[i]
+	}
+	return serving, nil
+}
+
+func (s *servingInfo) environmentName(id string) string {
+	for _, se := range s.environments {
+		if se.GetId() == id {
+			return se.GetName()
+		}
+	}
+	return ""
+}
+
+// environmentsFor are the serving environments the inference services of the registered models are deployed in
+func (s *servingInfo) environmentsFor(registeredModelIDs []string) []openapi.ServingEnvironment {
+	ses := []openapi.ServingEnvironment{}
+	for _, se := range s.environments {
+		for _, is := range s.inferenceServices {
+			if is.ServingEnvironmentId == se.GetId() && slices.Contains(registeredModelIDs, is.RegisteredModelId) {
+				ses = append(ses, se)
+				break
+			}
+		}
+	}
+	return ses
+}
+
+func (s *servingInfo) inferenceServicesFor(registeredModelID string) []openapi.InferenceService {
+	iss := []openapi.InferenceService{}
+	for _, is := range s.inferenceServices {
+		if is.RegisteredModelId == registeredModelID {
+			iss = append(iss, is)
+		}
+	}
+	return iss
+}
+
+// printSystems creates a System for each serving environment, writing them to their own catalog-info.yaml when there is
+// an output directory
+func printSystems(cfg *config.Config, owner, lifecycle string, environments []openapi.ServingEnvironment, cmd *cobra.Command) error {
+	if len(environments) == 0 {
+		return nil
+	}
+	out, err := backstage.NewModelOutput(cmd.OutOrStdout(), cfg.OutputDir, SERVING_ENVIRONMENTS_DIR)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	for _, se := range environments {
+		sysPop := &systemPopulator{owner: owner, lifecycle: lifecycle, namespace: cfg.CatalogNamespace, types: backstage.NewEntityTypes(cfg), servingEnvironment: &se}
+		err = backstage.PrintSystem(sysPop, out)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func callBackstagePrinters(cfg *config.Config, owner, lifecycle string, rm *openapi.RegisteredModel, mvs []openapi.ModelVersion, mas map[string][]openapi.ModelArtifact, serving *servingInfo, cmd *cobra.Command) error {
+	mas, docs := splitDocArtifacts(mas)
+	types := backstage.NewEntityTypes(cfg)
+	rules, err := parsePropertyRules(cfg.PropertyRules)
+	if err != nil {
+		return err
+	}
 	compPop := componentPopulator{}
 	compPop.owner = owner
 	compPop.lifecycle = lifecycle
+	compPop.types = types
+	compPop.rules = rules
+	compPop.namespace = cfg.CatalogNamespace
 	compPop.registeredModel = rm
 	compPop.modelVersions = mvs
 	compPop.modelArtifacts = mas
-	err := backstage.PrintComponent(&compPop, cmd)
+	compPop.docArtifacts = docs
+	compPop.serving = serving
+	compPop.inferenceServices = serving.inferenceServicesFor(rm.GetId())
+
+	out, err := backstage.NewModelOutput(cmd.OutOrStdout(), cfg.OutputDir, compPop.GetName())
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	err = backstage.PrintComponent(&compPop, out)
 	if err != nil {
 		return err
 	}
+	docPops := []backstage.CommonPopulator{&compPop}
 
-	resPop := resourcePopulator{}
-	resPop.owner = owner
-	resPop.lifecycle = lifecycle
-	resPop.registeredModel = rm
 	for _, mv := range mvs {
+		resPop := &resourcePopulator{}
+		resPop.owner = owner
+		resPop.lifecycle = lifecycle
+		resPop.types = types
+		resPop.rules = rules
+		resPop.namespace = cfg.CatalogNamespace
+		resPop.registeredModel = rm
 		resPop.modelVersion = &mv
 		m, _ := mas[*mv.Id]
 		resPop.modelArtifacts = m
-		err = backstage.PrintResource(&resPop, cmd)
+		err = backstage.PrintResource(resPop, out)
 		if err != nil {
 			return err
 		}
+		docPops = append(docPops, resPop)
 	}
 
-	apiPop := apiPopulator{}
-	apiPop.owner = owner
-	apiPop.lifecycle = lifecycle
-	apiPop.registeredModel = rm
-	for _, arr := range mas {
-		for _, ma := range arr {
+	for _, mv := range mvs {
+		for _, ma := range mas[mv.GetId()] {
+			apiPop := &apiPopulator{}
+			apiPop.owner = owner
+			apiPop.lifecycle = lifecycle
+			apiPop.types = types
+			apiPop.rules = rules
+			apiPop.namespace = cfg.CatalogNamespace
+			apiPop.registeredModel = rm
+			apiPop.modelVersion = &mv
 			apiPop.modelArtifact = &ma
-			err = backstage.PrintAPI(&apiPop, cmd)
+			err = backstage.PrintAPI(apiPop, out)
+			if err != nil {
+				return err
+			}
+			docPops = append(docPops, apiPop)
+		}
+	}
+
+	for _, is := range compPop.inferenceServices {
+		isPop := &inferenceServicePopulator{}
+		isPop.owner = owner
+		isPop.lifecycle = lifecycle
+		isPop.types = types
+		isPop.rules = rules
+		isPop.namespace = cfg.CatalogNamespace
+		isPop.registeredModel = rm
+		isPop.inferenceService = &is
+		isPop.environment = serving.environmentName(is.ServingEnvironmentId)
+		isPop.modelVersions = servedModelVersions(is, serving.serveModels[is.GetId()], mvs)
+		isPop.modelArtifacts = mas
+		err = backstage.PrintAPI(isPop, out)
+		if err != nil {
 			return err
 		}
+		docPops = append(docPops, isPop)
 	}
-	return nil
+	return out.WriteTechDocs(cmd.Context(), cfg, docPops...)
+}
+
+// servedModelVersions finds the model versions the inference service serves, falling back to the model version it
+// was deployed with when the registry has no serve models recorded for it
+func servedModelVersions(is openapi.InferenceService, sms []openapi.ServeModel, mvs []openapi.ModelVersion) []openapi.ModelVersion {
+	ids := []string{}
+	for _, sm := range sms {
+		ids = append(ids, sm.ModelVersionId)
+	}
+	if len(ids) == 0 && len(is.GetModelVersionId()) > 0 {
+		ids = append(ids, is.GetModelVersionId())
+	}
+	served := []openapi.ModelVersion{}
+	for _, id := range ids {
+		for _, mv := range mvs {
+			if mv.GetId() == id {
+				served = append(served, mv)
+			}
+		}
+	}
+	return served
+}
+
+// inferenceURL returns the inference endpoint recorded on the inference service, as the model registry does not track
+// the status of the deployment itself
+func inferenceURL(is *openapi.InferenceService) string {
+	value, ok := is.GetCustomProperties()[INFERENCE_URL_PROPERTY]
+	if !ok {
+		return ""
+	}
+	return metadataValueString(value)
+}
+
+// splitDocArtifacts separates the DocArtifacts, which the artifact listings return alongside the ModelArtifacts, so that
+// they feed the TechDocs instead of becoming API entities
+func splitDocArtifacts(mas map[string][]openapi.ModelArtifact) (map[string][]openapi.ModelArtifact, []openapi.ModelArtifact) {
+	models := map[string][]openapi.ModelArtifact{}
+	docs := []openapi.ModelArtifact{}
+	for id, arr := range mas {
+		models[id] = []openapi.ModelArtifact{}
+		for _, ma := range arr {
+			if ma.ArtifactType == DOC_ARTIFACT_TYPE {
+				docs = append(docs, ma)
+				continue
+			}
+			models[id] = append(models[id], ma)
+		}
+	}
+	return models, docs
+}
+
+func customProperties(props ...map[string]openapi.MetadataValue) map[string]string {
+	ret := map[string]string{}
+	for _, p := range props {
+		for key, value := range p {
+			ret[key] = metadataValueString(value)
+		}
+	}
+	return ret
+}
+
+func artifactFormat(ma openapi.ModelArtifact) string {
+	format := ma.GetModelFormatName()
+	if len(format) > 0 && len(ma.GetModelFormatVersion()) > 0 {
+		format = format + " " + ma.GetModelFormatVersion()
+	}
+	return format
+}
+
+// inferenceServiceSelector is the label selector of the pods KServe runs for the inference services
+func inferenceServiceSelector(iss []openapi.InferenceService) string {
+	names := []string{}
+	for _, is := range iss {
+		names = append(names, is.GetName())
+	}
+	switch len(names) {
+	case 0:
+		return ""
+	case 1:
+		return backstage.KSERVE_INFERENCE_SERVICE_LABEL + "=" + names[0]
+	}
+	return fmt.Sprintf("%s in (%s)", backstage.KSERVE_INFERENCE_SERVICE_LABEL, strings.Join(names, ","))
+}
+
+// artifactAnnotations adds the annotations of the model artifact behind an entity
+func artifactAnnotations(annotations map[string]string, ma openapi.ModelArtifact) map[string]string {
+	annotations[backstage.MODEL_ARTIFACT_ID_ANNOTATION] = ma.GetId()
+	annotations[backstage.MODEL_FORMAT_ANNOTATION] = ma.GetModelFormatName()
+	annotations[backstage.MODEL_FORMAT_VERSION_ANNOTATION] = ma.GetModelFormatVersion()
+	annotations[backstage.STORAGE_URI_ANNOTATION] = ma.GetUri()
+	return annotations
 }
 
 type commonPopulator struct {
 	owner           string
 	lifecycle       string
+	types           backstage.EntityTypes
 	registeredModel *openapi.RegisteredModel
+	rules           []propertyRule
+	namespace       string
+}
+
+func (pop *commonPopulator) GetNamespace() string {
+	return pop.namespace
+}
+
+// ref is the full 'kind:namespace/name' reference to another entity generated from the registry
+func (pop *commonPopulator) ref(kind, name string) string {
+	return backstage.Ref(kind, pop.namespace, name)
+}
+
+func (pop *commonPopulator) GetEntityTypes() backstage.EntityTypes {
+	return pop.types
 }
 
 func (pop *commonPopulator) GetOwner() string {
@@ -193,48 +595,130 @@ func (pop *commonPopulator) GetDescription() string {
 	return ""
 }
 
-// TODO won't have API until KubeFlow Model Registry gets us inferenceservice endpoints
 func (pop *commonPopulator) GetProvidedAPIs() []string {
 	return []string{}
 }
 
+func (pop *commonPopulator) GetAnnotations() map[string]string {
+	return map[string]string{
+		backstage.SOURCE_ANNOTATION:              backstage.SOURCE_KUBEFLOW,
+		backstage.REGISTERED_MODEL_ID_ANNOTATION: pop.registeredModel.GetId(),
+	}
+}
+
 type componentPopulator struct {
 	commonPopulator
-	modelVersions  []openapi.ModelVersion
-	modelArtifacts map[string][]openapi.ModelArtifact
+	modelVersions     []openapi.ModelVersion
+	modelArtifacts    map[string][]openapi.ModelArtifact
+	docArtifacts      []openapi.ModelArtifact
+	serving           *servingInfo
+	inferenceServices []openapi.InferenceService
 }
 
 func (pop *componentPopulator) GetName() string {
-	return pop.registeredModel.Name
+	return componentName(pop.registeredModel)
+}
+
+func (pop *componentPopulator) properties() mappedProperties {
+	return mapProperties(pop.rules, pop.registeredModel.GetCustomProperties())
 }
 
-// TODO Until we get the inferenceservice endpoint URL associated with the model registry related API won't have component links
 func (pop *componentPopulator) GetLinks() []backstage.EntityLink {
-	return []backstage.EntityLink{}
+	links := pop.properties().links
+	for _, is := range pop.inferenceServices {
+		if url := inferenceURL(&is); len(url) > 0 {
+			links = append(links, backstage.EntityLink{
+				URL:   url,
+				Title: is.GetName() + " " + backstage.LINK_REST_MODEL_SERVING_URL,
+				Icon:  backstage.LINK_ICON_WEBASSET,
+				Type:  backstage.LINK_TYPE_WEBSITE,
+			})
+		}
+	}
+	return links
+}
+
+func (pop *componentPopulator) GetProvidedAPIs() []string {
+	apis := []string{}
+	for _, is := range pop.inferenceServices {
+		apis = append(apis, pop.ref("api", inferenceServiceAPIName(pop.registeredModel, pop.serving.environmentName(is.ServingEnvironmentId), &is)))
+	}
+	return apis
+}
+
+// environment returns the serving environment when all the inference services of the model are deployed in the same one
+func (pop *componentPopulator) environment() string {
+	environment := ""
+	for _, is := range pop.inferenceServices {
+		name := pop.serving.environmentName(is.ServingEnvironmentId)
+		if len(environment) > 0 && environment != name {
+			return ""
+		}
+		environment = name
+	}
+	return environment
+}
+
+func (pop *componentPopulator) GetSystem() string {
+	if environment := pop.environment(); len(environment) > 0 {
+		return pop.ref("system", entityName(environment))
+	}
+	return ""
 }
 
 func (pop *componentPopulator) GetTags() []string {
-	tags := []string{}
-	for key, value := range pop.registeredModel.GetCustomProperties() {
-		tags = append(tags, fmt.Sprintf("%s:%v", key, value.GetActualInstance()))
+	return pop.properties().tags
+}
+
+func (pop *componentPopulator) GetLabels() map[string]string {
+	return pop.properties().labels
+}
+
+func (pop *componentPopulator) GetModelFormats() []string {
+	formats := []string{}
+	for _, mv := range pop.modelVersions {
+		for _, ma := range pop.modelArtifacts[*mv.Id] {
+			if format := artifactFormat(ma); len(format) > 0 {
+				formats = append(formats, format)
+			}
+		}
 	}
+	return formats
+}
 
-	return tags
+func (pop *componentPopulator) GetCustomProperties() map[string]string {
+	return customProperties(pop.registeredModel.GetCustomProperties())
 }
 
 func (pop *componentPopulator) GetDependsOn() []string {
 	depends := []string{}
 	for _, mv := range pop.modelVersions {
-		depends = append(depends, "resource:"+mv.Name)
+		depends = append(depends, pop.ref("resource", resourceName(pop.registeredModel, &mv)))
 	}
-	for _, mas := range pop.modelArtifacts {
-		for _, ma := range mas {
-			depends = append(depends, "api:"+*ma.Name)
+	for _, mv := range pop.modelVersions {
+		for _, ma := range pop.modelArtifacts[mv.GetId()] {
+			depends = append(depends, pop.ref("api", artifactAPIName(pop.registeredModel, &mv, &ma)))
 		}
 	}
 	return depends
 }
 
+// GetAnnotations describes the model version and artifact only when the registered model has just the one, and the
+// Kubernetes namespace only when all its inference services are in the same serving environment
+func (pop *componentPopulator) GetAnnotations() map[string]string {
+	annotations := pop.commonPopulator.GetAnnotations()
+	if len(pop.modelVersions) == 1 {
+		mv := pop.modelVersions[0]
+		annotations[backstage.MODEL_VERSION_ID_ANNOTATION] = mv.GetId()
+		if mas := pop.modelArtifacts[mv.GetId()]; len(mas) == 1 {
+			artifactAnnotations(annotations, mas[0])
+		}
+	}
+	annotations[backstage.KUBERNETES_NAMESPACE_ANNOTATION] = pop.environment()
+	annotations[backstage.KUBERNETES_LABEL_SELECTOR_ANNOTATION] = inferenceServiceSelector(pop.inferenceServices)
+	return mergeAnnotations(annotations, pop.properties().annotations)
+}
+
 func (pop *componentPopulator) GetTechdocRef() string {
 	return "./"
 }
@@ -243,6 +727,28 @@ func (pop *componentPopulator) GetDisplayName() string {
 	return fmt.Sprintf("The %s model server", pop.GetName())
 }
 
+// GetModelDocURLs returns the DocArtifact URIs along with the model card of any model artifact stored in Hugging Face
+func (pop *componentPopulator) GetModelDocURLs() []string {
+	urls := []string{}
+	for _, doc := range pop.docArtifacts {
+		if strings.HasPrefix(doc.GetUri(), "http") {
+			urls = append(urls, doc.GetUri())
+		}
+	}
+	for _, mv := range pop.modelVersions {
+		for _, ma := range pop.modelArtifacts[*mv.Id] {
+			if strings.HasPrefix(ma.GetUri(), backstage.HUGGINGFACE_STORAGE_PREFIX) {
+				urls = append(urls, backstage.HuggingFaceModelCardURL(ma.GetUri()))
+			}
+		}
+	}
+	return urls
+}
+
+func (pop *componentPopulator) GetServedModelName() string {
+	return pop.registeredModel.Name
+}
+
 type resourcePopulator struct {
 	commonPopulator
 	modelVersion   *openapi.ModelVersion
@@ -250,7 +756,28 @@ type resourcePopulator struct {
 }
 
 func (pop *resourcePopulator) GetName() string {
-	return pop.modelVersion.Name
+	return resourceName(pop.registeredModel, pop.modelVersion)
+}
+
+func (pop *resourcePopulator) properties() mappedProperties {
+	props := []map[string]openapi.MetadataValue{pop.modelVersion.GetCustomProperties()}
+	for _, ma := range pop.modelArtifacts {
+		props = append(props, ma.GetCustomProperties())
+	}
+	return mapProperties(pop.rules, props...)
+}
+
+func (pop *resourcePopulator) GetAnnotations() map[string]string {
+	annotations := pop.commonPopulator.GetAnnotations()
+	annotations[backstage.MODEL_VERSION_ID_ANNOTATION] = pop.modelVersion.GetId()
+	if len(pop.modelArtifacts) == 1 {
+		artifactAnnotations(annotations, pop.modelArtifacts[0])
+	}
+	return mergeAnnotations(annotations, pop.properties().annotations)
+}
+
+func (pop *resourcePopulator) GetLabels() map[string]string {
+	return pop.properties().labels
 }
 
 func (pop *resourcePopulator) GetTechdocRef() string {
@@ -258,7 +785,7 @@ func (pop *resourcePopulator) GetTechdocRef() string {
 }
 
 func (pop *resourcePopulator) GetLinks() []backstage.EntityLink {
-	links := []backstage.EntityLink{}
+	links := pop.properties().links
 	for _, ma := range pop.modelArtifacts {
 		if ma.Uri != nil {
 			links = append(links, backstage.EntityLink{
@@ -273,44 +800,76 @@ func (pop *resourcePopulator) GetLinks() []backstage.EntityLink {
 }
 
 func (pop *resourcePopulator) GetTags() []string {
-	tags := []string{}
-	for key := range pop.modelVersion.GetCustomProperties() {
-		tags = append(tags, key)
-	}
+	return pop.properties().tags
+}
 
+func (pop *resourcePopulator) GetModelFormats() []string {
+	formats := []string{}
 	for _, ma := range pop.modelArtifacts {
-		for k := range ma.GetCustomProperties() {
-			tags = append(tags, k)
+		if format := artifactFormat(ma); len(format) > 0 {
+			formats = append(formats, format)
 		}
 	}
-	return tags
+	return formats
+}
+
+func (pop *resourcePopulator) GetCustomProperties() map[string]string {
+	props := []map[string]openapi.MetadataValue{pop.modelVersion.GetCustomProperties()}
+	for _, ma := range pop.modelArtifacts {
+		props = append(props, ma.GetCustomProperties())
+	}
+	return customProperties(props...)
 }
 
 func (pop *resourcePopulator) GetDependencyOf() []string {
-	return []string{fmt.Sprintf("component:%s", pop.registeredModel.Name)}
+	return []string{pop.ref("component", componentName(pop.registeredModel))}
 }
 
 func (pop *resourcePopulator) GetDisplayName() string {
 	return fmt.Sprintf("The %s ai model", pop.GetName())
 }
 
-// TODO Until we get the inferenceservice endpoint URL associated with the model registry related API won't have much for Backstage API here
 type apiPopulator struct {
 	commonPopulator
+	modelVersion  *openapi.ModelVersion
 	modelArtifact *openapi.ModelArtifact
 }
 
 func (pop *apiPopulator) GetName() string {
-	return *pop.modelArtifact.Name
+	return artifactAPIName(pop.registeredModel, pop.modelVersion, pop.modelArtifact)
 }
 
 func (pop *apiPopulator) GetDependencyOf() []string {
-	return []string{fmt.Sprintf("component:%s", pop.registeredModel.Name)}
+	return []string{pop.ref("component", componentName(pop.registeredModel))}
+}
+
+func (pop *apiPopulator) GetModelFormats() []string {
+	if format := artifactFormat(*pop.modelArtifact); len(format) > 0 {
+		return []string{format}
+	}
+	return []string{}
+}
+
+func (pop *apiPopulator) GetCustomProperties() map[string]string {
+	return customProperties(pop.modelArtifact.GetCustomProperties())
 }
 
 func (pop *apiPopulator) GetDefinition() string {
 	// definition must be set to something to pass backstage validation
-	return "no-definition-yet"
+	return backstage.API_DEFINITION_PLACEHOLDER
+}
+
+func (pop *apiPopulator) properties() mappedProperties {
+	return mapProperties(pop.rules, pop.modelArtifact.GetCustomProperties())
+}
+
+func (pop *apiPopulator) GetAnnotations() map[string]string {
+	annotations := artifactAnnotations(pop.commonPopulator.GetAnnotations(), *pop.modelArtifact)
+	return mergeAnnotations(annotations, pop.properties().annotations)
+}
+
+func (pop *apiPopulator) GetLabels() map[string]string {
+	return pop.properties().labels
 }
 
 func (pop *apiPopulator) GetTechdocRef() string {
@@ -318,13 +877,192 @@ func (pop *apiPopulator) GetTechdocRef() string {
 }
 
 func (pop *apiPopulator) GetTags() []string {
-	return []string{}
+	return pop.properties().tags
 }
 
 func (pop *apiPopulator) GetLinks() []backstage.EntityLink {
-	return []backstage.EntityLink{}
+	return pop.properties().links
 }
 
 func (pop *apiPopulator) GetDisplayName() string {
 	return fmt.Sprintf("The %s openapi", pop.GetName())
 }
+
+type inferenceServicePopulator struct {
+	commonPopulator
+	inferenceService *openapi.InferenceService
+	environment      string
+	modelVersions    []openapi.ModelVersion
+	modelArtifacts   map[string][]openapi.ModelArtifact
+}
+
+func (pop *inferenceServicePopulator) GetName() string {
+	return inferenceServiceAPIName(pop.registeredModel, pop.environment, pop.inferenceService)
+}
+
+func (pop *inferenceServicePopulator) GetDescription() string {
+	if len(pop.inferenceService.GetDescription()) > 0 {
+		return pop.inferenceService.GetDescription()
+	}
+	return pop.commonPopulator.GetDescription()
+}
+
+func (pop *inferenceServicePopulator) GetDependencyOf() []string {
+	return []string{pop.ref("component", componentName(pop.registeredModel))}
+}
+
+func (pop *inferenceServicePopulator) GetModelFormats() []string {
+	formats := []string{}
+	for _, mv := range pop.modelVersions {
+		for _, ma := range pop.modelArtifacts[mv.GetId()] {
+			if format := artifactFormat(ma); len(format) > 0 {
+				formats = append(formats, format)
+			}
+		}
+	}
+	return formats
+}
+
+func (pop *inferenceServicePopulator) GetCustomProperties() map[string]string {
+	props := customProperties(pop.inferenceService.GetCustomProperties())
+	if len(pop.inferenceService.GetRuntime()) > 0 {
+		props["runtime"] = pop.inferenceService.GetRuntime()
+	}
+	if pop.inferenceService.DesiredState != nil {
+		props["desiredState"] = string(*pop.inferenceService.DesiredState)
+	}
+	versions := []string{}
+	for _, mv := range pop.modelVersions {
+		versions = append(versions, mv.Name)
+	}
+	if len(versions) > 0 {
+		props["modelVersions"] = strings.Join(versions, ",")
+	}
+	return props
+}
+
+func (pop *inferenceServicePopulator) GetDefinition() string {
+	url := inferenceURL(pop.inferenceService)
+	if len(url) == 0 {
+		return backstage.API_DEFINITION_PLACEHOLDER
+	}
+	defBytes, _ := util.FetchURL(strings.TrimSuffix(url, "/") + "/openapi.json")
+	dst := bytes.Buffer{}
+	json.Indent(&dst, defBytes, "", "    ")
+	if dst.Len() == 0 {
+		return backstage.API_DEFINITION_PLACEHOLDER
+	}
+	return dst.String()
+}
+
+func (pop *inferenceServicePopulator) GetAnnotations() map[string]string {
+	annotations := pop.commonPopulator.GetAnnotations()
+	annotations[backstage.MODEL_VERSION_ID_ANNOTATION] = pop.inferenceService.GetModelVersionId()
+	if mas := pop.modelArtifacts[pop.inferenceService.GetModelVersionId()]; len(mas) == 1 {
+		artifactAnnotations(annotations, mas[0])
+	}
+	annotations[backstage.SERVING_RUNTIME_ANNOTATION] = pop.inferenceService.GetRuntime()
+	annotations[backstage.KUBERNETES_NAMESPACE_ANNOTATION] = pop.environment
+	annotations[backstage.KUBERNETES_NAME_ANNOTATION] = pop.inferenceService.GetName()
+	annotations[backstage.KUBERNETES_ID_ANNOTATION] = pop.inferenceService.GetName()
+	annotations[backstage.KUBERNETES_LABEL_SELECTOR_ANNOTATION] = inferenceServiceSelector([]openapi.InferenceService{*pop.inferenceService})
+	return annotations
+}
+
+func (pop *inferenceServicePopulator) GetTechdocRef() string {
+	return "api/"
+}
+
+func (pop *inferenceServicePopulator) GetTags() []string {
+	tags := []string{}
+	if runtime := backstage.SanitizeTag(pop.inferenceService.GetRuntime()); len(runtime) > 0 {
+		tags = append(tags, runtime)
+	}
+	return tags
+}
+
+func (pop *inferenceServicePopulator) GetLinks() []backstage.EntityLink {
+	links := []backstage.EntityLink{}
+	if url := inferenceURL(pop.inferenceService); len(url) > 0 {
+		links = append(links, backstage.EntityLink{
+			URL:   url,
+			Title: backstage.LINK_API_URL,
+			Icon:  backstage.LINK_ICON_WEBASSET,
+			Type:  backstage.LINK_TYPE_WEBSITE,
+		})
+	}
+	return links
+}
+
+func (pop *inferenceServicePopulator) GetDisplayName() string {
+	return fmt.Sprintf("The %s openapi", pop.GetName())
+}
+
+func (pop *inferenceServicePopulator) GetSystem() string {
+	if len(pop.environment) > 0 {
+		return pop.ref("system", entityName(pop.environment))
+	}
+	return ""
+}
+
+type systemPopulator struct {
+	owner              string
+	lifecycle          string
+	namespace          string
+	types              backstage.EntityTypes
+	servingEnvironment *openapi.ServingEnvironment
+}
+
+func (pop *systemPopulator) GetNamespace() string {
+	return pop.namespace
+}
+
+func (pop *systemPopulator) GetEntityTypes() backstage.EntityTypes {
+	return pop.types
+}
+
+func (pop *systemPopulator) GetOwner() string {
+	return pop.owner
+}
+
+func (pop *systemPopulator) GetLifecycle() string {
+	return pop.lifecycle
+}
+
+func (pop *systemPopulator) GetName() string {
+	return entityName(pop.servingEnvironment.GetName())
+}
+
+func (pop *systemPopulator) GetDescription() string {
+	if len(pop.servingEnvironment.GetDescription()) > 0 {
+		return pop.servingEnvironment.GetDescription()
+	}
+	return fmt.Sprintf("Kubeflow Model Registry serving environment %s", pop.GetName())
+}
+
+func (pop *systemPopulator) GetLinks() []backstage.EntityLink {
+	return []backstage.EntityLink{}
+}
+
+func (pop *systemPopulator) GetTags() []string {
+	return []string{}
+}
+
+func (pop *systemPopulator) GetProvidedAPIs() []string {
+	return []string{}
+}
+
+func (pop *systemPopulator) GetAnnotations() map[string]string {
+	return map[string]string{
+		backstage.SOURCE_ANNOTATION:               backstage.SOURCE_KUBEFLOW,
+		backstage.KUBERNETES_NAMESPACE_ANNOTATION: pop.servingEnvironment.GetName(),
+	}
+}
+
+func (pop *systemPopulator) GetTechdocRef() string {
+	return ""
+}
+
+func (pop *systemPopulator) GetDisplayName() string {
+	return fmt.Sprintf("The %s serving environment", pop.GetName())
+}
@@ -3,6 +3,7 @@ package kubeflowmodelregistry
 import (
 	"github.com/gabemontero/backstage-ai-cli/pkg/config"
 	"github.com/gabemontero/backstage-ai-cli/test/stub"
+	"github.com/kubeflow/model-registry/pkg/openapi"
 	"github.com/spf13/cobra"
 	"strings"
 	"testing"
@@ -64,6 +65,74 @@ func TestNewCmd(t *testing.T) {
 	}
 }
 
+func TestNewCmdFilters(t *testing.T) {
+	ts := CreateGetServer(t)
+	defer ts.Close()
+	for _, tc := range []struct {
+		name     string
+		cfg      *config.Config
+		errorStr string
+		outStr   []string
+		notOut   []string
+	}{
+		{
+			name:   "live",
+			cfg:    &config.Config{ModelState: "LIVE"},
+			outStr: []string{"kind: System", "kind: Component", "name: model-1-v1"},
+		},
+		{
+			// the serving environment only comes with the models deployed in it
+			name:   "archived",
+			cfg:    &config.Config{ModelState: "ARCHIVED"},
+			notOut: []string{"kind: System", "kind: Component", "kind: Resource"},
+		},
+		{
+			name:   "by name",
+			cfg:    &config.Config{ModelNames: []string{"model-1"}},
+			outStr: []string{"name: model-1"},
+		},
+		{
+			name: "catalog namespace",
+			cfg:  &config.Config{CatalogNamespace: "team-a"},
+			outStr: []string{"namespace: team-a", "owner: user:default/kube:admin", "- resource:team-a/model-1-v1",
+				"- api:team-a/model-1-v1-artifact", "- api:team-a/my-ds-project-model-1-is", "system: system:team-a/my-ds-project", "- component:team-a/model-1"},
+		},
+		{
+			name:     "bad catalog namespace",
+			cfg:      &config.Config{CatalogNamespace: "Team_A"},
+			errorStr: "invalid catalog namespace",
+		},
+		{
+			name:     "bad state",
+			cfg:      &config.Config{ModelState: "DELETED"},
+			errorStr: "invalid value 'DELETED' for RegisteredModelState",
+		},
+		{
+			name:     "bad order by",
+			cfg:      &config.Config{OrderBy: "NAME"},
+			errorStr: "invalid value 'NAME' for OrderByField",
+		},
+	} {
+		SetupKubeflowTestRESTClient(ts, tc.cfg)
+		_, stdout, stderr, err := stub.ExecuteCommandC(NewCmd(tc.cfg), "owner", "lifecycle")
+		if len(tc.errorStr) > 0 {
+			if err == nil || !strings.Contains(stderr, tc.errorStr) {
+				t.Errorf("%s: expected error %q but got %v", tc.name, tc.errorStr, err)
+			}
+			continue
+		}
+		AssertError(t, err)
+		for _, str := range tc.outStr {
+			AssertContains(t, stdout, str)
+		}
+		for _, str := range tc.notOut {
+			if strings.Contains(stdout, str) {
+				t.Errorf("%s: did not expect %q in %s", tc.name, str, stdout)
+			}
+		}
+	}
+}
+
 func testHelpOK(stdout string, cmd *cobra.Command) bool {
 	if strings.Contains(stdout, cmd.Long) {
 		return true
@@ -73,60 +142,173 @@ func testHelpOK(stdout string, cmd *cobra.Command) bool {
 
 const (
 	listOutput = `apiVersion: backstage.io/v1alpha1
+kind: System
+metadata:
+  annotations:
+    ai.backstage.io/source: kubeflow-model-registry
+    backstage.io/kubernetes-namespace: my-ds-project
+  description: Kubeflow Model Registry serving environment my-ds-project
+  labels:
+    ai.backstage.io/source: kubeflow-model-registry
+  name: my-ds-project
+spec:
+  owner: user:default/owner
+  profile:
+    displayName: The my-ds-project serving environment
+  type: model-serving-environment
+---
+apiVersion: backstage.io/v1alpha1
 kind: Component
 metadata:
   annotations:
-    backstage.io/techdocs-ref: ./
+    ai.backstage.io/model-artifact-id: "1"
+    ai.backstage.io/model-format: tensorflow
+    ai.backstage.io/model-format-version: v1
+    ai.backstage.io/model-version-id: "2"
+    ai.backstage.io/registered-model-id: "1"
+    ai.backstage.io/source: kubeflow-model-registry
+    ai.backstage.io/storage-uri: https://foo.com
+    backstage.io/kubernetes-label-selector: serving.kserve.io/inferenceservice=model-1-is
+    backstage.io/kubernetes-namespace: my-ds-project
+    backstage.io/techdocs-ref: dir:./
+    modelregistry.kubeflow.org/foo: bar
   description: dummy model 1
+  labels:
+    ai.backstage.io/source: kubeflow-model-registry
+  links:
+  - icon: WebAsset
+    title: model-1-is REST model serving URL
+    type: website
+    url: https://kserve.com
   name: model-1
-  tags:
-  - foo:&{bar MetadataStringValue}
 spec:
   dependsOn:
-  - resource:v1
-  - api:model-1-v1-artifact
+  - resource:default/model-1-v1
+  - api:default/model-1-v1-artifact
   lifecycle: lifecycle
-  owner: user:kube:admin
+  owner: user:default/kube:admin
   profile:
     displayName: The model-1 model server
+  providesApis:
+  - api:default/my-ds-project-model-1-is
+  system: system:default/my-ds-project
   type: model-server
 ---
 apiVersion: backstage.io/v1alpha1
 kind: Resource
 metadata:
   annotations:
-    backstage.io/techdocs-ref: resource/
+    ai.backstage.io/model-artifact-id: "1"
+    ai.backstage.io/model-format: tensorflow
+    ai.backstage.io/model-format-version: v1
+    ai.backstage.io/model-version-id: "2"
+    ai.backstage.io/registered-model-id: "1"
+    ai.backstage.io/source: kubeflow-model-registry
+    ai.backstage.io/storage-uri: https://foo.com
+    backstage.io/techdocs-ref: dir:resource/
   description: dummy model 1
+  labels:
+    ai.backstage.io/source: kubeflow-model-registry
   links:
   - icon: WebAsset
     title: version 1
     type: website
     url: https://foo.com
-  name: v1
+  name: model-1-v1
 spec:
   dependencyOf:
-  - component:model-1
+  - component:default/model-1
   lifecycle: lifecycle
-  owner: user:kube:admin
+  owner: user:default/kube:admin
   profile:
-    displayName: The v1 ai model
+    displayName: The model-1-v1 ai model
   type: api-model
 ---
 apiVersion: backstage.io/v1alpha1
 kind: API
 metadata:
   annotations:
-    backstage.io/techdocs-ref: api/
+    ai.backstage.io/model-artifact-id: "1"
+    ai.backstage.io/model-format: tensorflow
+    ai.backstage.io/model-format-version: v1
+    ai.backstage.io/registered-model-id: "1"
+    ai.backstage.io/source: kubeflow-model-registry
+    ai.backstage.io/storage-uri: https://foo.com
+    backstage.io/techdocs-ref: dir:api/
   description: dummy model 1
+  labels:
+    ai.backstage.io/source: kubeflow-model-registry
   name: model-1-v1-artifact
 spec:
   definition: no-definition-yet
   dependencyOf:
-  - component:model-1
+  - component:default/model-1
   lifecycle: lifecycle
-  owner: user:kube:admin
+  owner: user:default/kube:admin
   profile:
     displayName: The model-1-v1-artifact openapi
   type: openapi
+---
+apiVersion: backstage.io/v1alpha1
+kind: API
+metadata:
+  annotations:
+    ai.backstage.io/kubernetes-name: model-1-is
+    ai.backstage.io/model-artifact-id: "1"
+    ai.backstage.io/model-format: tensorflow
+    ai.backstage.io/model-format-version: v1
+    ai.backstage.io/model-version-id: "2"
+    ai.backstage.io/registered-model-id: "1"
+    ai.backstage.io/serving-runtime: ovms
+    ai.backstage.io/source: kubeflow-model-registry
+    ai.backstage.io/storage-uri: https://foo.com
+    backstage.io/kubernetes-id: model-1-is
+    backstage.io/kubernetes-label-selector: serving.kserve.io/inferenceservice=model-1-is
+    backstage.io/kubernetes-namespace: my-ds-project
+    backstage.io/techdocs-ref: dir:api/
+  description: dummy model 1
+  labels:
+    ai.backstage.io/source: kubeflow-model-registry
+  links:
+  - icon: WebAsset
+    title: API URL
+    type: website
+    url: https://kserve.com
+  name: my-ds-project-model-1-is
+  tags:
+  - ovms
+spec:
+  definition: no-definition-yet
+  dependencyOf:
+  - component:default/model-1
+  lifecycle: lifecycle
+  owner: user:default/kube:admin
+  profile:
+    displayName: The my-ds-project-model-1-is openapi
+  system: system:default/my-ds-project
+  type: openapi
+---
 `
 )
+
+func TestEnvironmentsFor(t *testing.T) {
+	serving := &servingInfo{
+		environments: []openapi.ServingEnvironment{{Id: openapi.PtrString("1"), Name: openapi.PtrString("dev")}, {Id: openapi.PtrString("2"), Name: openapi.PtrString("prod")}},
+		inferenceServices: []openapi.InferenceService{
+			{RegisteredModelId: "10", ServingEnvironmentId: "1"},
+			{RegisteredModelId: "20", ServingEnvironmentId: "2"},
+			{RegisteredModelId: "30", ServingEnvironmentId: "2"},
+		},
+	}
+	names := func(ses []openapi.ServingEnvironment) []string {
+		result := []string{}
+		for _, se := range ses {
+			result = append(result, se.GetName())
+		}
+		return result
+	}
+	AssertEqual(t, []string{"dev"}, names(serving.environmentsFor([]string{"10"})))
+	AssertEqual(t, []string{"prod"}, names(serving.environmentsFor([]string{"20", "30"})))
+	AssertEqual(t, []string{"dev", "prod"}, names(serving.environmentsFor([]string{"30", "10"})))
+	AssertEqual(t, []string{}, names(serving.environmentsFor([]string{"40"})))
+}
@@ -1,21 +1,25 @@
 package kubeflowmodelregistry
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/kubeflow/model-registry/pkg/openapi"
 )
 
-func (k *KubeFlowRESTClientWrapper) ListModelArtifacts(id string) ([]openapi.ModelArtifact, error) {
-	buf, err := k.getFromModelRegistry(k.RootURL + fmt.Sprintf(LIST_ARTFIACTS_OFF_VERSIONS_URI, id))
+func (k *KubeFlowRESTClientWrapper) ListModelArtifacts(ctx context.Context, id string) ([]openapi.ModelArtifact, error) {
+	mas := []openapi.ModelArtifact{}
+	err := k.listAllPages(ctx, k.RootURL+fmt.Sprintf(LIST_ARTFIACTS_OFF_VERSIONS_URI, id), func(buf []byte) (string, error) {
+		list := openapi.ModelArtifactList{}
+		err := json.Unmarshal(buf, &list)
+		if err != nil {
+			return "", err
+		}
+		mas = append(mas, list.Items...)
+		return list.NextPageToken, nil
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	mas := openapi.ModelArtifactList{}
-	err = json.Unmarshal(buf, &mas)
-	if err != nil {
-		return nil, err
-	}
-	return mas.Items, err
+	return mas, nil
 }
@@ -1,21 +1,25 @@
 package kubeflowmodelregistry
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/kubeflow/model-registry/pkg/openapi"
 )
 
-func (k *KubeFlowRESTClientWrapper) ListModelVersions(id string) ([]openapi.ModelVersion, error) {
-	buf, err := k.getFromModelRegistry(k.RootURL + fmt.Sprintf(LIST_VERSIONS_OFF_REG_MODELS_URI, id))
+func (k *KubeFlowRESTClientWrapper) ListModelVersions(ctx context.Context, id string) ([]openapi.ModelVersion, error) {
+	mvs := []openapi.ModelVersion{}
+	err := k.listAllPages(ctx, k.RootURL+fmt.Sprintf(LIST_VERSIONS_OFF_REG_MODELS_URI, id), func(buf []byte) (string, error) {
+		list := openapi.ModelVersionList{}
+		err := json.Unmarshal(buf, &list)
+		if err != nil {
+			return "", err
+		}
+		mvs = append(mvs, list.Items...)
+		return list.NextPageToken, nil
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	mvs := openapi.ModelVersionList{}
-	err = json.Unmarshal(buf, &mvs)
-	if err != nil {
-		return nil, err
-	}
-	return mvs.Items, err
+	return mvs, nil
 }
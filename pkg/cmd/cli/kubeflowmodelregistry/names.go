@@ -0,0 +1,53 @@
+package kubeflowmodelregistry
+
+import (
+	"fmt"
+	"github.com/gabemontero/backstage-ai-cli/pkg/cmd/cli/backstage"
+	"github.com/kubeflow/model-registry/pkg/openapi"
+	"hash/fnv"
+	"strings"
+)
+
+// entityName joins the registry names into a valid entity name.  Names too long for Backstage are truncated and end
+// with a hash of the full name, so models whose names only differ past the cut do not collide.
+func entityName(parts ...string) string {
+	joined := strings.Join(parts, "-")
+	if len(joined) <= backstage.MAX_TAG_LENGTH {
+		return backstage.SanitizeName(joined)
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(joined))
+	suffix := fmt.Sprintf("-%08x", hash.Sum32())
+	return backstage.SanitizeName(joined[:backstage.MAX_TAG_LENGTH-len(suffix)]) + suffix
+}
+
+func componentName(rm *openapi.RegisteredModel) string {
+	return entityName(rm.Name)
+}
+
+// resourceName qualifies the model version with its registered model, as versions are typically just named 'v1'
+func resourceName(rm *openapi.RegisteredModel, mv *openapi.ModelVersion) string {
+	return entityName(rm.Name, mv.Name)
+}
+
+// inferenceServiceAPIName qualifies the inference service with its serving environment and registered model, as the
+// same name can be deployed in several environments or for several models.  The model is left out when the inference
+// service is already named after it.
+func inferenceServiceAPIName(rm *openapi.RegisteredModel, environment string, is *openapi.InferenceService) string {
+	parts := []string{}
+	if len(environment) > 0 {
+		parts = append(parts, environment)
+	}
+	if !strings.HasPrefix(entityName(is.GetName()), componentName(rm)+"-") {
+		parts = append(parts, rm.Name)
+	}
+	return entityName(append(parts, is.GetName())...)
+}
+
+// artifactAPIName qualifies the model artifact with its model version, unless the artifact is already named after it
+func artifactAPIName(rm *openapi.RegisteredModel, mv *openapi.ModelVersion, ma *openapi.ModelArtifact) string {
+	if name := entityName(ma.GetName()); strings.HasPrefix(name, resourceName(rm, mv)+"-") {
+		return name
+	}
+	return entityName(rm.Name, mv.Name, ma.GetName())
+}
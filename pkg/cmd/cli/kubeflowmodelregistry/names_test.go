@@ -0,0 +1,43 @@
+package kubeflowmodelregistry
+
+import (
+	"github.com/gabemontero/backstage-ai-cli/pkg/cmd/cli/backstage"
+	"github.com/kubeflow/model-registry/pkg/openapi"
+	"strings"
+	"testing"
+)
+
+func TestEntityNames(t *testing.T) {
+	rm := &openapi.RegisteredModel{Name: "My Model"}
+	mv := &openapi.ModelVersion{Name: "v1"}
+	artifact := "onnx"
+	prefixed := "My Model-v1-onnx"
+
+	AssertEqual(t, "My-Model", componentName(rm))
+	AssertEqual(t, "My-Model-v1", resourceName(rm, mv))
+	AssertEqual(t, "My-Model-v1-onnx", artifactAPIName(rm, mv, &openapi.ModelArtifact{Name: &artifact}))
+	AssertEqual(t, "My-Model-v1-onnx", artifactAPIName(rm, mv, &openapi.ModelArtifact{Name: &prefixed}))
+
+	// the versions of different models no longer collide
+	AssertEqual(t, "other-v1", resourceName(&openapi.RegisteredModel{Name: "other"}, mv))
+
+	// nor do inference services of the same name in different environments, or for different models
+	is := &openapi.InferenceService{Name: openapi.PtrString("predictor")}
+	AssertEqual(t, "dev-My-Model-predictor", inferenceServiceAPIName(rm, "dev", is))
+	AssertEqual(t, "prod-My-Model-predictor", inferenceServiceAPIName(rm, "prod", is))
+	AssertEqual(t, "dev-other-predictor", inferenceServiceAPIName(&openapi.RegisteredModel{Name: "other"}, "dev", is))
+	AssertEqual(t, "dev-My-Model-is", inferenceServiceAPIName(rm, "dev", &openapi.InferenceService{Name: openapi.PtrString("My Model-is")}))
+	AssertEqual(t, "My-Model-predictor", inferenceServiceAPIName(rm, "", is))
+}
+
+func TestEntityNameTooLong(t *testing.T) {
+	long := strings.Repeat("model", 15)
+	first, second := entityName(long, "v1"), entityName(long, "v2")
+	if len(first) > backstage.MAX_TAG_LENGTH || backstage.ValidateName(first) != nil {
+		t.Errorf("expected a valid name, got %q", first)
+	}
+	if first == second {
+		t.Errorf("expected different names for different versions, got %q for both", first)
+	}
+	AssertEqual(t, first, entityName(long, "v1"))
+}
@@ -0,0 +1,180 @@
+package kubeflowmodelregistry
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/gabemontero/backstage-ai-cli/pkg/cmd/cli/backstage"
+	"github.com/kubeflow/model-registry/pkg/openapi"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	propertyTargetTag        = "tag"
+	propertyTargetLabel      = "label"
+	propertyTargetAnnotation = "annotation"
+	propertyTargetLink       = "link"
+	propertyTargetIgnore     = "ignore"
+
+	// PROPERTY_KEY_PREFIX prefixes the label and annotation keys of the custom properties mapped without a key
+	PROPERTY_KEY_PREFIX = "modelregistry.kubeflow.org/"
+)
+
+// propertyRule sends the custom properties whose names match the pattern, a name or path.Match glob, to the target
+type propertyRule struct {
+	pattern string
+	target  string
+	// key is the label or annotation key, or the link title
+	key string
+}
+
+// parsePropertyRules reads the '<property>=<target>[:<key>]' rules, where the first rule matching a property wins
+func parsePropertyRules(values []string) ([]propertyRule, error) {
+	rules := []propertyRule{}
+	for _, value := range values {
+		pattern, target, found := strings.Cut(value, "=")
+		if !found || len(strings.TrimSpace(pattern)) == 0 {
+			return nil, fmt.Errorf("invalid property rule %q, expected '<property>=<target>[:<key>]'", value)
+		}
+		target, key, _ := strings.Cut(target, ":")
+		rule := propertyRule{pattern: strings.TrimSpace(pattern), target: strings.ToLower(strings.TrimSpace(target)), key: strings.TrimSpace(key)}
+		switch rule.target {
+		case propertyTargetTag, propertyTargetLabel, propertyTargetAnnotation, propertyTargetLink, propertyTargetIgnore:
+		default:
+			return nil, fmt.Errorf("invalid property rule %q, the target must be %s, %s, %s, %s, or %s", value,
+				propertyTargetTag, propertyTargetLabel, propertyTargetAnnotation, propertyTargetLink, propertyTargetIgnore)
+		}
+		if _, err := path.Match(rule.pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid property rule %q: %s", value, err.Error())
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// metadataValueString renders the value held by the custom property, whichever of the MetadataValue types it is.
+// Struct values, which the registry holds as base64 encoded JSON, are decoded; proto values are left base64 encoded,
+// as their bytes are not text.
+func metadataValueString(value openapi.MetadataValue) string {
+	switch {
+	case value.MetadataStringValue != nil:
+		return value.MetadataStringValue.StringValue
+	case value.MetadataIntValue != nil:
+		return value.MetadataIntValue.IntValue
+	case value.MetadataDoubleValue != nil:
+		return strconv.FormatFloat(value.MetadataDoubleValue.DoubleValue, 'f', -1, 64)
+	case value.MetadataBoolValue != nil:
+		return strconv.FormatBool(value.MetadataBoolValue.BoolValue)
+	case value.MetadataStructValue != nil:
+		encoded := value.MetadataStructValue.StructValue
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || !json.Valid(decoded) {
+			return encoded
+		}
+		compact := bytes.Buffer{}
+		json.Compact(&compact, decoded)
+		return compact.String()
+	case value.MetadataProtoValue != nil:
+		return value.MetadataProtoValue.ProtoValue
+	}
+	return ""
+}
+
+// mappedProperties are the entity fields the custom properties map to
+type mappedProperties struct {
+	tags        []string
+	labels      map[string]string
+	annotations map[string]string
+	links       []backstage.EntityLink
+}
+
+// defaultPropertyTarget is where a property goes when no rule matches: the properties without a value, which is how
+// the registry UI stores labels, become tags, URLs become links, and everything else an annotation
+func defaultPropertyTarget(value openapi.MetadataValue, text string) string {
+	switch {
+	case value.MetadataStringValue != nil && len(text) == 0:
+		return propertyTargetTag
+	case value.MetadataStringValue != nil && (strings.HasPrefix(text, "http://") || strings.HasPrefix(text, "https://")):
+		return propertyTargetLink
+	}
+	return propertyTargetAnnotation
+}
+
+func propertyKey(rule propertyRule, name string) string {
+	if len(rule.key) > 0 {
+		return rule.key
+	}
+	return PROPERTY_KEY_PREFIX + backstage.SanitizeName(name)
+}
+
+// mapProperties applies the rules to the custom properties, in name order so the output is stable
+func mapProperties(rules []propertyRule, props ...map[string]openapi.MetadataValue) mappedProperties {
+	mapped := mappedProperties{tags: []string{}, labels: map[string]string{}, annotations: map[string]string{}, links: []backstage.EntityLink{}}
+	values := map[string]openapi.MetadataValue{}
+	for _, p := range props {
+		for name, value := range p {
+			values[name] = value
+		}
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		text := metadataValueString(values[name])
+		rule := propertyRule{target: defaultPropertyTarget(values[name], text)}
+		for _, r := range rules {
+			if matched, _ := path.Match(r.pattern, name); matched {
+				rule = r
+				break
+			}
+		}
+		switch rule.target {
+		case propertyTargetTag:
+			tag := name
+			if len(text) > 0 {
+				tag = name + ":" + text
+			}
+			if tag = backstage.SanitizeTag(tag); len(tag) > 0 {
+				mapped.tags = append(mapped.tags, tag)
+			}
+		case propertyTargetLabel:
+			if value := backstage.SanitizeName(text); len(value) > 0 {
+				mapped.labels[propertyKey(rule, name)] = value
+			}
+		case propertyTargetAnnotation:
+			mapped.annotations[propertyKey(rule, name)] = text
+		case propertyTargetLink:
+			if len(text) == 0 {
+				continue
+			}
+			title := rule.key
+			if len(title) == 0 {
+				title = name
+			}
+			mapped.links = append(mapped.links, backstage.EntityLink{
+				URL:   text,
+				Title: title,
+				Icon:  backstage.LINK_ICON_WEBASSET,
+				Type:  backstage.LINK_TYPE_WEBSITE,
+			})
+		}
+	}
+	return mapped
+}
+
+// mergeAnnotations adds the annotations mapped from the custom properties, without replacing those the populator sets
+func mergeAnnotations(annotations, mapped map[string]string) map[string]string {
+	for key, value := range mapped {
+		if _, found := annotations[key]; !found {
+			annotations[key] = value
+		}
+	}
+	return annotations
+}
@@ -0,0 +1,112 @@
+package kubeflowmodelregistry
+
+import (
+	"encoding/base64"
+	"github.com/gabemontero/backstage-ai-cli/pkg/cmd/cli/backstage"
+	"github.com/kubeflow/model-registry/pkg/openapi"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func stringValue(value string) openapi.MetadataValue {
+	return openapi.MetadataStringValueAsMetadataValue(openapi.NewMetadataStringValue(value, "MetadataStringValue"))
+}
+
+func TestMetadataValueString(t *testing.T) {
+	for _, tc := range []struct {
+		value    openapi.MetadataValue
+		expected string
+	}{
+		{value: stringValue("bar"), expected: "bar"},
+		{value: openapi.MetadataIntValueAsMetadataValue(openapi.NewMetadataIntValue("42", "MetadataIntValue")), expected: "42"},
+		{value: openapi.MetadataDoubleValueAsMetadataValue(openapi.NewMetadataDoubleValue(0.975, "MetadataDoubleValue")), expected: "0.975"},
+		{value: openapi.MetadataBoolValueAsMetadataValue(openapi.NewMetadataBoolValue(true, "MetadataBoolValue")), expected: "true"},
+		{
+			value:    openapi.MetadataStructValueAsMetadataValue(openapi.NewMetadataStructValue(base64.StdEncoding.EncodeToString([]byte(`{"epochs": 10}`)), "MetadataStructValue")),
+			expected: `{"epochs":10}`,
+		},
+		{value: openapi.MetadataStructValueAsMetadataValue(openapi.NewMetadataStructValue("not-base64!", "MetadataStructValue")), expected: "not-base64!"},
+		{value: openapi.MetadataProtoValueAsMetadataValue(openapi.NewMetadataProtoValue("my.Type", "AQID", "MetadataProtoValue")), expected: "AQID"},
+		{value: openapi.MetadataValue{}, expected: ""},
+	} {
+		if actual := metadataValueString(tc.value); actual != tc.expected {
+			t.Errorf("expected %q, got %q", tc.expected, actual)
+		}
+	}
+}
+
+func TestParsePropertyRules(t *testing.T) {
+	rules, err := parsePropertyRules([]string{"accuracy=annotation", "data*=Link:Training dataset", "team=label:example.com/team"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err.Error())
+	}
+	expected := []propertyRule{
+		{pattern: "accuracy", target: propertyTargetAnnotation},
+		{pattern: "data*", target: propertyTargetLink, key: "Training dataset"},
+		{pattern: "team", target: propertyTargetLabel, key: "example.com/team"},
+	}
+	if !reflect.DeepEqual(expected, rules) {
+		t.Errorf("expected rules %v, got %v", expected, rules)
+	}
+
+	for _, value := range []string{"accuracy", "=tag", "accuracy=metric", "[=tag"} {
+		if _, err = parsePropertyRules([]string{value}); err == nil || !strings.Contains(err.Error(), "invalid property rule") {
+			t.Errorf("expected an error for %q, got %v", value, err)
+		}
+	}
+}
+
+func TestMapProperties(t *testing.T) {
+	props := map[string]openapi.MetadataValue{
+		"Accuracy":   openapi.MetadataDoubleValueAsMetadataValue(openapi.NewMetadataDoubleValue(0.975, "MetadataDoubleValue")),
+		"dataset":    stringValue("https://example.com/data"),
+		"homepage":   stringValue("https://example.com"),
+		"internal":   stringValue("secret"),
+		"production": stringValue(""),
+		"stage":      stringValue("Prod Ready"),
+		"team":       stringValue("Data Science"),
+	}
+	rules, _ := parsePropertyRules([]string{"dataset=link:Training dataset", "team=label:example.com/team", "internal=ignore", "stage=tag"})
+	mapped := mapProperties(rules, props)
+
+	if expected := []string{"production", "stage:prod-ready"}; !reflect.DeepEqual(expected, mapped.tags) {
+		t.Errorf("expected tags %v, got %v", expected, mapped.tags)
+	}
+	if expected := map[string]string{"example.com/team": "Data-Science"}; !reflect.DeepEqual(expected, mapped.labels) {
+		t.Errorf("expected labels %v, got %v", expected, mapped.labels)
+	}
+	if expected := map[string]string{PROPERTY_KEY_PREFIX + "Accuracy": "0.975"}; !reflect.DeepEqual(expected, mapped.annotations) {
+		t.Errorf("expected annotations %v, got %v", expected, mapped.annotations)
+	}
+	expectedLinks := []backstage.EntityLink{
+		{URL: "https://example.com/data", Title: "Training dataset", Icon: backstage.LINK_ICON_WEBASSET, Type: backstage.LINK_TYPE_WEBSITE},
+		{URL: "https://example.com", Title: "homepage", Icon: backstage.LINK_ICON_WEBASSET, Type: backstage.LINK_TYPE_WEBSITE},
+	}
+	if !reflect.DeepEqual(expectedLinks, mapped.links) {
+		t.Errorf("expected links %v, got %v", expectedLinks, mapped.links)
+	}
+}
+
+func TestMergeAnnotations(t *testing.T) {
+	annotations := mergeAnnotations(map[string]string{backstage.SOURCE_ANNOTATION: backstage.SOURCE_KUBEFLOW},
+		map[string]string{backstage.SOURCE_ANNOTATION: "other", PROPERTY_KEY_PREFIX + "foo": "bar"})
+	expected := map[string]string{backstage.SOURCE_ANNOTATION: backstage.SOURCE_KUBEFLOW, PROPERTY_KEY_PREFIX + "foo": "bar"}
+	if !reflect.DeepEqual(expected, annotations) {
+		t.Errorf("expected annotations %v, got %v", expected, annotations)
+	}
+}
+
+func TestInferenceServiceTags(t *testing.T) {
+	for runtime, tags := range map[string][]string{
+		"vLLM_ServingRuntime": {"vllm-servingruntime"},
+		"ovms":                {"ovms"},
+		"__":                  {},
+		"":                    {},
+	} {
+		pop := &inferenceServicePopulator{inferenceService: &openapi.InferenceService{Runtime: openapi.PtrString(runtime)}}
+		if actual := pop.GetTags(); !reflect.DeepEqual(tags, actual) {
+			t.Errorf("expected tags %v for runtime %q, got %v", tags, runtime, actual)
+		}
+	}
+}
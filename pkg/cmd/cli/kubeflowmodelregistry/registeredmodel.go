@@ -1,27 +1,49 @@
 package kubeflowmodelregistry
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/kubeflow/model-registry/pkg/openapi"
+	nurl "net/url"
 )
 
-func (k *KubeFlowRESTClientWrapper) ListRegisteredModels() ([]openapi.RegisteredModel, error) {
-	buf, err := k.getFromModelRegistry(k.RootURL + LIST_REG_MODEL_URI)
+func (k *KubeFlowRESTClientWrapper) ListRegisteredModels(ctx context.Context) ([]openapi.RegisteredModel, error) {
+	rms := []openapi.RegisteredModel{}
+	err := k.listAllPages(ctx, k.RootURL+LIST_REG_MODEL_URI, func(buf []byte) (string, error) {
+		list := openapi.RegisteredModelList{}
+		err := json.Unmarshal(buf, &list)
+		if err != nil {
+			return "", err
+		}
+		rms = append(rms, list.Items...)
+		return list.NextPageToken, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rms, nil
+}
+
+func (k *KubeFlowRESTClientWrapper) GetRegisteredModel(ctx context.Context, registeredModelID string) (*openapi.RegisteredModel, error) {
+	buf, err := k.getFromModelRegistry(ctx, k.RootURL+fmt.Sprintf(GET_REG_MODEL_URI, registeredModelID))
 	if err != nil {
 		return nil, err
 	}
 
-	rmList := openapi.RegisteredModelList{}
-	err = json.Unmarshal(buf, &rmList)
+	rm := openapi.RegisteredModel{}
+	err = json.Unmarshal(buf, &rm)
 	if err != nil {
 		return nil, err
 	}
-	return rmList.Items, err
+	return &rm, err
 }
 
-func (k *KubeFlowRESTClientWrapper) GetRegisteredModel(registeredModelID string) (*openapi.RegisteredModel, error) {
-	buf, err := k.getFromModelRegistry(k.RootURL + fmt.Sprintf(GET_REG_MODEL_URI, registeredModelID))
+// FindRegisteredModel looks up a registered model by its name
+func (k *KubeFlowRESTClientWrapper) FindRegisteredModel(ctx context.Context, name string) (*openapi.RegisteredModel, error) {
+	params := nurl.Values{}
+	params.Set(NAME_PARAM, name)
+	buf, err := k.getFromModelRegistry(ctx, k.RootURL+FIND_REG_MODEL_URI+"?"+params.Encode())
 	if err != nil {
 		return nil, err
 	}
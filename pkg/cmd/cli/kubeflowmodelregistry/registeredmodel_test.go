@@ -0,0 +1,68 @@
+package kubeflowmodelregistry
+
+import (
+	"context"
+	"fmt"
+	"github.com/gabemontero/backstage-ai-cli/pkg/config"
+	"net/http"
+	"testing"
+)
+
+func TestListRegisteredModelsPaging(t *testing.T) {
+	pages := map[string]string{
+		"":   `{"items":[{"id":"1","name":"model-1"},{"id":"2","name":"model-2"}],"nextPageToken":"p2","pageSize":2,"size":2}`,
+		"p2": `{"items":[{"id":"3","name":"model-3"},{"id":"4","name":"model-4"}],"nextPageToken":"p3","pageSize":2,"size":2}`,
+		"p3": `{"items":[{"id":"5","name":"model-5"}],"nextPageToken":"","pageSize":2,"size":1}`,
+	}
+	ts := CreateTestServer(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		AssertEqual(t, "2", query.Get(PAGE_SIZE_PARAM))
+		AssertEqual(t, "CREATE_TIME", query.Get(ORDER_BY_PARAM))
+		AssertEqual(t, "DESC", query.Get(SORT_ORDER_PARAM))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(pages[query.Get(NEXT_PAGE_TOKEN_PARAM)]))
+	})
+	defer ts.Close()
+
+	cfg := &config.Config{PageSize: 2, OrderBy: "CREATE_TIME", SortOrder: "DESC"}
+	SetupKubeflowTestRESTClient(ts, cfg)
+	rms, err := SetupKubeflowRESTClient(cfg).ListRegisteredModels(context.Background())
+	AssertError(t, err)
+	AssertEqual(t, 5, len(rms))
+	for i, rm := range rms {
+		AssertEqual(t, fmt.Sprintf("model-%d", i+1), rm.Name)
+	}
+}
+
+func TestListRegisteredModelsRepeatedToken(t *testing.T) {
+	calls := 0
+	ts := CreateTestServer(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items":[{"id":"1","name":"model-1"}],"nextPageToken":"same","pageSize":1,"size":1}`))
+	})
+	defer ts.Close()
+
+	cfg := &config.Config{}
+	SetupKubeflowTestRESTClient(ts, cfg)
+	rms, err := SetupKubeflowRESTClient(cfg).ListRegisteredModels(context.Background())
+	AssertError(t, err)
+	AssertEqual(t, 2, calls)
+	AssertEqual(t, 2, len(rms))
+}
+
+func TestFindRegisteredModel(t *testing.T) {
+	ts := CreateTestServer(func(w http.ResponseWriter, r *http.Request) {
+		AssertEqual(t, BASE_URI+FIND_REG_MODEL_URI, r.URL.Path)
+		AssertEqual(t, "my model", r.URL.Query().Get(NAME_PARAM))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"7","name":"my model"}`))
+	})
+	defer ts.Close()
+
+	cfg := &config.Config{}
+	SetupKubeflowTestRESTClient(ts, cfg)
+	rm, err := SetupKubeflowRESTClient(cfg).FindRegisteredModel(context.Background(), "my model")
+	AssertError(t, err)
+	AssertEqual(t, "7", rm.GetId())
+}
@@ -1,26 +1,49 @@
 package kubeflowmodelregistry
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"github.com/gabemontero/backstage-ai-cli/pkg/config"
+	"github.com/gabemontero/backstage-ai-cli/pkg/util"
 	"github.com/go-resty/resty/v2"
 	"k8s.io/klog/v2"
+	nurl "net/url"
 	"os"
+	"strconv"
+	"time"
 )
 
 const (
-	BASE_URI                         = "/api/model_registry/v1alpha3"
-	GET_REG_MODEL_URI                = "/registered_models/%s"
-	LIST_VERSIONS_OFF_REG_MODELS_URI = "/registered_models/%s/versions"
-	LIST_ARTFIACTS_OFF_VERSIONS_URI  = "/model_versions/%s/artifacts"
-	LIST_REG_MODEL_URI               = "/registered_models"
+	BASE_URI                                     = "/api/model_registry/v1alpha3"
+	GET_REG_MODEL_URI                            = "/registered_models/%s"
+	LIST_VERSIONS_OFF_REG_MODELS_URI             = "/registered_models/%s/versions"
+	LIST_ARTFIACTS_OFF_VERSIONS_URI              = "/model_versions/%s/artifacts"
+	LIST_REG_MODEL_URI                           = "/registered_models"
+	FIND_REG_MODEL_URI                           = "/registered_model"
+	LIST_SERVING_ENVIRONMENTS_URI                = "/serving_environments"
+	LIST_INFERENCE_SERVICES_URI                  = "/inference_services"
+	LIST_SERVE_MODELS_OFF_INFERENCE_SERVICES_URI = "/inference_services/%s/serves"
+	DOC_ARTIFACT_TYPE                            = "doc-artifact"
+	INFERENCE_URL_PROPERTY                       = "url"
+	SERVING_ENVIRONMENTS_DIR                     = "serving-environments"
+	DEFAULT_CONCURRENCY                          = 4
+
+	PAGE_SIZE_PARAM       = "pageSize"
+	ORDER_BY_PARAM        = "orderBy"
+	SORT_ORDER_PARAM      = "sortOrder"
+	NEXT_PAGE_TOKEN_PARAM = "nextPageToken"
+	NAME_PARAM            = "name"
 )
 
 type KubeFlowRESTClientWrapper struct {
-	RESTClient *resty.Client
-	RootURL    string
-	Token      string
+	RESTClient     *resty.Client
+	RootURL        string
+	Token          string
+	RequestTimeout time.Duration
+	PageSize       int
+	OrderBy        string
+	SortOrder      string
 }
 
 func SetupKubeflowRESTClient(cfg *config.Config) *KubeFlowRESTClientWrapper {
@@ -30,14 +53,21 @@ func SetupKubeflowRESTClient(cfg *config.Config) *KubeFlowRESTClientWrapper {
 		os.Exit(1)
 	}
 	kubeFlowRESTClient := &KubeFlowRESTClientWrapper{
-		Token:      cfg.StoreToken,
-		RootURL:    cfg.StoreURL + BASE_URI,
-		RESTClient: cfg.KubeflowRESTClient,
+		Token:          cfg.StoreToken,
+		RootURL:        cfg.StoreURL + BASE_URI,
+		RESTClient:     cfg.KubeflowRESTClient,
+		RequestTimeout: requestTimeout(cfg),
+		PageSize:       cfg.PageSize,
+		OrderBy:        cfg.OrderBy,
+		SortOrder:      cfg.SortOrder,
 	}
 	if cfg.KubeflowRESTClient != nil {
 		return kubeFlowRESTClient
 	}
-	cfg.KubeflowRESTClient = resty.New()
+	cfg.KubeflowRESTClient = util.ConfigureRESTClient(resty.New(), cfg)
+	if cfg.RequestTimeout > 0 {
+		cfg.KubeflowRESTClient.SetTimeout(cfg.RequestTimeout)
+	}
 	kubeFlowRESTClient.RESTClient = cfg.KubeflowRESTClient
 	if cfg.KubeflowRESTClient == nil {
 		klog.Errorf("Unable to get Kubeflow REST client wrapper")
@@ -53,8 +83,21 @@ func SetupKubeflowRESTClient(cfg *config.Config) *KubeFlowRESTClientWrapper {
 	return kubeFlowRESTClient
 }
 
-func (k *KubeFlowRESTClientWrapper) getFromModelRegistry(url string) ([]byte, error) {
-	resp, err := k.RESTClient.R().SetAuthToken(k.Token).Get(url)
+// requestTimeout is --request-timeout when set, which overrides --timeout for the Kubeflow Model Registry
+func requestTimeout(cfg *config.Config) time.Duration {
+	if cfg.RequestTimeout > 0 {
+		return cfg.RequestTimeout
+	}
+	return cfg.Timeout
+}
+
+func (k *KubeFlowRESTClientWrapper) getFromModelRegistry(ctx context.Context, url string) ([]byte, error) {
+	if k.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, k.RequestTimeout)
+		defer cancel()
+	}
+	resp, err := k.RESTClient.R().SetContext(ctx).SetAuthToken(k.Token).Get(url)
 	if err != nil {
 		return nil, err
 	}
@@ -68,3 +111,39 @@ func (k *KubeFlowRESTClientWrapper) getFromModelRegistry(url string) ([]byte, er
 	return resp.Body(), err
 
 }
+
+// listAllPages follows the nextPageToken of a list call until the registry has returned every page, handing each page
+// to decode, which returns the token for the next one
+func (k *KubeFlowRESTClientWrapper) listAllPages(ctx context.Context, url string, decode func(buf []byte) (string, error)) error {
+	params := nurl.Values{}
+	if k.PageSize > 0 {
+		params.Set(PAGE_SIZE_PARAM, strconv.Itoa(k.PageSize))
+	}
+	if len(k.OrderBy) > 0 {
+		params.Set(ORDER_BY_PARAM, k.OrderBy)
+	}
+	if len(k.SortOrder) > 0 {
+		params.Set(SORT_ORDER_PARAM, k.SortOrder)
+	}
+	seen := map[string]bool{}
+	for {
+		pageURL := url
+		if len(params) > 0 {
+			pageURL = url + "?" + params.Encode()
+		}
+		buf, err := k.getFromModelRegistry(ctx, pageURL)
+		if err != nil {
+			return err
+		}
+		token, err := decode(buf)
+		if err != nil {
+			return err
+		}
+		// a registry handing back a token it already gave us would otherwise have us loop forever
+		if len(token) == 0 || seen[token] {
+			return nil
+		}
+		seen[token] = true
+		params.Set(NEXT_PAGE_TOKEN_PARAM, token)
+	}
+}
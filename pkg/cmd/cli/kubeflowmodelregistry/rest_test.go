@@ -2,13 +2,16 @@ package kubeflowmodelregistry
 
 import (
 	"bufio"
+	"context"
 	"github.com/gabemontero/backstage-ai-cli/pkg/config"
+	"github.com/gabemontero/backstage-ai-cli/pkg/util"
 	"github.com/go-resty/resty/v2"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 const (
@@ -17,6 +20,9 @@ const (
 	TestJSONStringRegisteredModelOneLine    = `{"items":[{"createTimeSinceEpoch":"1731103949567","customProperties":{"foo":{"metadataType":"MetadataStringValue","string_value":"bar"}},"description":"dummy model 1","id":"1","lastUpdateTimeSinceEpoch":"1731103975700","name":"model-1","owner":"kube:admin","state":"LIVE"}],"nextPageToken":"","pageSize":0,"size":1}`
 	TestJSONStringRegisteredModelOneLineGet = `{"createTimeSinceEpoch":"1731103949567","customProperties":{"foo":{"metadataType":"MetadataStringValue","string_value":"bar"}},"description":"dummy model 1","id":"1","lastUpdateTimeSinceEpoch":"1731103975700","name":"model-1","owner":"kube:admin","state":"LIVE"}`
 	TestJSONStringModelVersionOneLine       = `{"items":[{"author":"kube:admin","createTimeSinceEpoch":"1731103949724","customProperties":{},"description":"version 1","id":"2","lastUpdateTimeSinceEpoch":"1731103949724","name":"v1","registeredModelId":"1","state":"LIVE"}],"nextPageToken":"","pageSize":0,"size":1}`
+	TestJSONStringServingEnvironmentOneLine = `{"items":[{"createTimeSinceEpoch":"1731103949500","customProperties":{},"description":"","id":"1","lastUpdateTimeSinceEpoch":"1731103949500","name":"my-ds-project"}],"nextPageToken":"","pageSize":0,"size":1}`
+	TestJSONStringInferenceServiceOneLine   = `{"items":[{"createTimeSinceEpoch":"1731103950000","customProperties":{"url":{"metadataType":"MetadataStringValue","string_value":"https://kserve.com"}},"desiredState":"DEPLOYED","id":"3","lastUpdateTimeSinceEpoch":"1731103950000","modelVersionId":"2","name":"model-1-is","registeredModelId":"1","runtime":"ovms","servingEnvironmentId":"1"}],"nextPageToken":"","pageSize":0,"size":1}`
+	TestJSONStringServeModelOneLine         = `{"items":[{"createTimeSinceEpoch":"1731103950100","customProperties":{},"id":"4","lastKnownState":"RUNNING","lastUpdateTimeSinceEpoch":"1731103950100","modelVersionId":"2","name":"model-1-is-serve"}],"nextPageToken":"","pageSize":0,"size":1}`
 	TestJSONStringModelArtifactOneLine      = `{"items":[{"artifactType":"model-artifact","createTimeSinceEpoch":"1731103949909","customProperties":{},"description":"version 1","id":"1","lastUpdateTimeSinceEpoch":"1731103949909","modelFormatName":"tensorflow","modelFormatVersion":"v1","name":"model-1-v1-artifact","state":"LIVE","uri":"https://foo.com"}],"nextPageToken":"","pageSize":0,"size":1}`
 )
 
@@ -44,6 +50,14 @@ func CreateGetServer(t *testing.T) *httptest.Server {
 				_, _ = w.Write([]byte(TestJSONStringModelVersionOneLine))
 			case strings.HasSuffix(r.URL.Path, "/artifacts"):
 				_, _ = w.Write([]byte(TestJSONStringModelArtifactOneLine))
+			case strings.HasSuffix(r.URL.Path, LIST_SERVING_ENVIRONMENTS_URI):
+				_, _ = w.Write([]byte(TestJSONStringServingEnvironmentOneLine))
+			case strings.HasSuffix(r.URL.Path, LIST_INFERENCE_SERVICES_URI):
+				_, _ = w.Write([]byte(TestJSONStringInferenceServiceOneLine))
+			case strings.HasSuffix(r.URL.Path, "/serves"):
+				_, _ = w.Write([]byte(TestJSONStringServeModelOneLine))
+			case strings.HasSuffix(r.URL.Path, FIND_REG_MODEL_URI):
+				_, _ = w.Write([]byte(TestJSONStringRegisteredModelOneLineGet))
 			case strings.Contains(r.URL.Path, LIST_REG_MODEL_URI):
 				_, _ = w.Write([]byte(TestJSONStringRegisteredModelOneLineGet))
 			}
@@ -127,3 +141,115 @@ func LogResponse(t *testing.T, resp *resty.Response) {
 	t.Logf("Response Cookies: %v", resp.Cookies())
 	t.Logf("Response Body: %v", resp)
 }
+
+func TestRetries(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		status         int
+		header         map[string]string
+		calls          int
+		minWait        time.Duration
+		maxWait        time.Duration
+		generatesError bool
+	}{
+		{
+			name:    "throttled with retry after",
+			status:  http.StatusTooManyRequests,
+			header:  map[string]string{util.RETRY_AFTER_HEADER: "1"},
+			calls:   3,
+			minWait: time.Second,
+		},
+		{
+			name:    "throttled with retry after in the past",
+			status:  http.StatusTooManyRequests,
+			header:  map[string]string{util.RETRY_AFTER_HEADER: time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)},
+			calls:   3,
+			maxWait: time.Second,
+		},
+		{
+			name:   "server error",
+			status: http.StatusServiceUnavailable,
+			calls:  3,
+		},
+		{
+			name:           "server error past the retries",
+			status:         http.StatusInternalServerError,
+			calls:          10,
+			generatesError: true,
+		},
+		{
+			name:           "client error",
+			status:         http.StatusNotFound,
+			calls:          1,
+			generatesError: true,
+		},
+	} {
+		calls := 0
+		ts := CreateTestServer(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls < 3 || tc.status == http.StatusNotFound || tc.calls > 3 {
+				for k, v := range tc.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(tc.status)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(TestJSONStringRegisteredModelOneLine))
+		})
+
+		cfg := &config.Config{Retries: 2, RetryWait: 10 * time.Millisecond, RetryMaxWait: 2 * time.Second}
+		cfg.StoreURL = ts.URL
+		kfmr := SetupKubeflowRESTClient(cfg)
+		start := time.Now()
+		rms, err := kfmr.ListRegisteredModels(context.Background())
+		switch {
+		case tc.generatesError && err == nil:
+			t.Errorf("%s: expected an error", tc.name)
+		case !tc.generatesError && err != nil:
+			t.Errorf("%s: unexpected error %s", tc.name, err.Error())
+		case !tc.generatesError:
+			AssertEqual(t, 1, len(rms))
+		}
+		AssertEqual(t, min(tc.calls, cfg.Retries+1), calls)
+		if time.Since(start) < tc.minWait {
+			t.Errorf("%s: retried after %s, sooner than the Retry-After of %s", tc.name, time.Since(start), tc.minWait)
+		}
+		if tc.maxWait > 0 && time.Since(start) > tc.maxWait {
+			t.Errorf("%s: retried after %s, later than %s", tc.name, time.Since(start), tc.maxWait)
+		}
+		ts.Close()
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	AssertEqual(t, 10*time.Second, requestTimeout(&config.Config{Timeout: 10 * time.Second}))
+	AssertEqual(t, 5*time.Second, requestTimeout(&config.Config{Timeout: 10 * time.Second, RequestTimeout: 5 * time.Second}))
+	AssertEqual(t, time.Duration(0), requestTimeout(&config.Config{}))
+}
+
+func TestSharedRateLimiter(t *testing.T) {
+	ts := CreateGetServer(t)
+	defer ts.Close()
+
+	cfg := &config.Config{RateLimit: 20, RateBurst: 1}
+	cfg.StoreURL = ts.URL
+	kfmr := SetupKubeflowRESTClient(cfg)
+	// a second client from the same config shares the token bucket
+	other := util.ConfigureRESTClient(resty.New(), cfg)
+	if util.RateLimiter(cfg) != cfg.Limiter {
+		t.Errorf("expected the rate limiter to be shared through the config")
+	}
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := kfmr.ListRegisteredModels(context.Background())
+		AssertError(t, err)
+		_, err = other.R().Get(ts.URL + BASE_URI + LIST_REG_MODEL_URI)
+		AssertError(t, err)
+	}
+	// 6 requests with a burst of 1 at 20 per second need at least 5 intervals of 50ms
+	if elapsed := time.Since(start); elapsed < 250*time.Millisecond {
+		t.Errorf("6 requests took %s, faster than the rate limit allows", elapsed)
+	}
+}
@@ -0,0 +1,24 @@
+package kubeflowmodelregistry
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/kubeflow/model-registry/pkg/openapi"
+)
+
+func (k *KubeFlowRESTClientWrapper) ListServingEnvironments(ctx context.Context) ([]openapi.ServingEnvironment, error) {
+	ses := []openapi.ServingEnvironment{}
+	err := k.listAllPages(ctx, k.RootURL+LIST_SERVING_ENVIRONMENTS_URI, func(buf []byte) (string, error) {
+		list := openapi.ServingEnvironmentList{}
+		err := json.Unmarshal(buf, &list)
+		if err != nil {
+			return "", err
+		}
+		ses = append(ses, list.Items...)
+		return list.NextPageToken, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ses, nil
+}
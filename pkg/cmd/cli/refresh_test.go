@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"context"
+	"github.com/gabemontero/backstage-ai-cli/pkg/cmd/cli/backstage"
+	"github.com/gabemontero/backstage-ai-cli/pkg/config"
+	"testing"
+	"time"
+)
+
+func TestRefreshEntities(t *testing.T) {
+	refreshPollInterval = time.Millisecond
+	client := &fakeCatalog{
+		locations: map[string]backstage.Location{"my-location-id": {ID: "my-location-id", Type: "url", Target: "https://my-repo/my.yaml"}},
+		entities: map[string][]backstage.Entity{"https://my-repo/my.yaml": {
+			{Kind: backstage.KindComponent, Metadata: backstage.EntityMeta{Name: "my-model"}},
+			{Kind: backstage.KindAPI, Metadata: backstage.EntityMeta{Name: "my-model", Namespace: "ai"}},
+		}},
+		etags: map[string]string{},
+	}
+
+	str, err := refreshEntities(context.Background(), client, &config.Config{}, []string{"resource:default/my-resource", "my-location-id"})
+	assertContains(t, err, str, "resource:default/my-resource: refresh requested", "Component:default/my-model: refresh requested", "API:ai/my-model: refresh requested")
+	if len(client.refreshed) != 3 {
+		t.Errorf("expected 3 refreshes but got %v", client.refreshed)
+	}
+
+	str, err = refreshEntities(context.Background(), client, &config.Config{RefreshWait: true, RefreshWaitTimeout: time.Second}, []string{"resource:default/my-resource"})
+	assertContains(t, err, str, "resource:default/my-resource: processed (etag -refreshed-refreshed)")
+
+	_, err = refreshEntities(context.Background(), client, &config.Config{}, []string{"missing-location-id"})
+	if err == nil {
+		t.Error("expected an error for the unknown location")
+	}
+}
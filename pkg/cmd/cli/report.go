@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/gabemontero/backstage-ai-cli/pkg/cmd/cli/backstage"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	reportFormatTable    = "table"
+	reportFormatJSON     = "json"
+	reportFormatMarkdown = "markdown"
+)
+
+// reportFacets are the entity fields the inventory counts, in the order they are reported
+var reportFacets = []string{"spec.type", "spec.owner", "spec.lifecycle", "metadata.tags", "metadata.namespace"}
+
+// inventoryReport summarizes the AI related entities in the catalog
+type inventoryReport struct {
+	Counts            map[string][]backstage.FacetCount `json:"counts"`
+	ModelsWithoutAPIs []string                          `json:"modelsWithoutAPIs"`
+	PlaceholderAPIs   []string                          `json:"placeholderAPIs"`
+	ProcessingErrors  []entityErrors                    `json:"processingErrors"`
+}
+
+type entityErrors struct {
+	Ref    string   `json:"ref"`
+	Errors []string `json:"errors"`
+}
+
+func buildReport(ctx context.Context, client backstage.CatalogClient, types backstage.EntityTypes) (*inventoryReport, error) {
+	filters := types.ModelFilters()
+	counts, err := client.GetEntityFacets(ctx, reportFacets, filters...)
+	if err != nil {
+		return nil, err
+	}
+	for _, values := range counts {
+		sort.SliceStable(values, func(i, j int) bool {
+			if values[i].Count != values[j].Count {
+				return values[i].Count > values[j].Count
+			}
+			return values[i].Value < values[j].Value
+		})
+	}
+	entities, err := client.QueryEntities(ctx, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &inventoryReport{
+		Counts:            counts,
+		ModelsWithoutAPIs: []string{},
+		PlaceholderAPIs:   []string{},
+		ProcessingErrors:  []entityErrors{},
+	}
+	for _, entity := range entities {
+		ref := backstage.EntityRef(entity)
+		switch strings.ToLower(entity.Kind) {
+		case "component":
+			if !providesAPI(entity) {
+				report.ModelsWithoutAPIs = append(report.ModelsWithoutAPIs, ref)
+			}
+		case "api":
+			definition, _ := entity.Spec["definition"].(string)
+			if len(strings.TrimSpace(definition)) == 0 || definition == backstage.API_DEFINITION_PLACEHOLDER {
+				report.PlaceholderAPIs = append(report.PlaceholderAPIs, ref)
+			}
+		}
+		if entity.Status == nil {
+			continue
+		}
+		errs := []string{}
+		for _, item := range entity.Status.Items {
+			if item.Level != "error" {
+				continue
+			}
+			msg := item.Message
+			if item.Error != nil {
+				msg = fmt.Sprintf("%s: %s", item.Error.Name, item.Error.Message)
+			}
+			errs = append(errs, msg)
+		}
+		if len(errs) > 0 {
+			report.ProcessingErrors = append(report.ProcessingErrors, entityErrors{Ref: ref, Errors: errs})
+		}
+	}
+	sort.Strings(report.ModelsWithoutAPIs)
+	sort.Strings(report.PlaceholderAPIs)
+	sort.Slice(report.ProcessingErrors, func(i, j int) bool {
+		return report.ProcessingErrors[i].Ref < report.ProcessingErrors[j].Ref
+	})
+	return report, nil
+}
+
+// providesAPI checks both the relations Backstage derived and the spec, as the relations are not there until the
+// entity is processed
+func providesAPI(entity backstage.Entity) bool {
+	for _, relation := range entity.Relations {
+		if relation.Type == "providesApi" {
+			return true
+		}
+	}
+	apis, _ := entity.Spec["providesApis"].([]interface{})
+	return len(apis) > 0
+}
+
+// reportEntities renders the inventory of the Components, Resources, and APIs with any of the AI related types in the
+// requested format
+func reportEntities(ctx context.Context, client backstage.CatalogClient, types backstage.EntityTypes, format string) (string, error) {
+	switch format {
+	case reportFormatTable, reportFormatJSON, reportFormatMarkdown:
+	default:
+		return "", fmt.Errorf("unsupported --output value %q, use %q, %q, or %q", format, reportFormatTable, reportFormatJSON, reportFormatMarkdown)
+	}
+	report, err := buildReport(ctx, client, types)
+	if err != nil {
+		return "", err
+	}
+	switch format {
+	case reportFormatJSON:
+		return formatJSON(report, nil)
+	case reportFormatMarkdown:
+		return formatReportMarkdown(report), nil
+	}
+	return formatReportTable(report), nil
+}
+
+func formatReportTable(report *inventoryReport) string {
+	buffer := &bytes.Buffer{}
+	w := tabwriter.NewWriter(buffer, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "FACET\tVALUE\tCOUNT")
+	for _, facet := range reportFacets {
+		for _, value := range report.Counts[facet] {
+			fmt.Fprintf(w, "%s\t%s\t%d\n", facet, value.Value, value.Count)
+		}
+	}
+	w.Flush()
+
+	writeList := func(title string, refs []string) {
+		fmt.Fprintf(buffer, "\n%s: %d\n", title, len(refs))
+		for _, ref := range refs {
+			fmt.Fprintf(buffer, "    %s\n", ref)
+		}
+	}
+	writeList("Models without APIs", report.ModelsWithoutAPIs)
+	writeList("APIs with placeholder definitions", report.PlaceholderAPIs)
+	fmt.Fprintf(buffer, "\nEntities with processing errors: %d\n", len(report.ProcessingErrors))
+	for _, entity := range report.ProcessingErrors {
+		fmt.Fprintf(buffer, "    %s\n", entity.Ref)
+		for _, msg := range entity.Errors {
+			fmt.Fprintf(buffer, "        %s\n", msg)
+		}
+	}
+	return buffer.String()
+}
+
+func formatReportMarkdown(report *inventoryReport) string {
+	buffer := &bytes.Buffer{}
+	fmt.Fprintln(buffer, "# AI Model Inventory")
+	fmt.Fprintln(buffer)
+	fmt.Fprintln(buffer, "## Counts")
+	fmt.Fprintln(buffer)
+	fmt.Fprintln(buffer, "| Facet | Value | Count |")
+	fmt.Fprintln(buffer, "| --- | --- | ---: |")
+	for _, facet := range reportFacets {
+		for _, value := range report.Counts[facet] {
+			fmt.Fprintf(buffer, "| %s | %s | %d |\n", facet, markdownEscape(value.Value), value.Count)
+		}
+	}
+
+	writeList := func(title string, refs []string) {
+		fmt.Fprintf(buffer, "\n## %s (%d)\n\n", title, len(refs))
+		if len(refs) == 0 {
+			fmt.Fprintln(buffer, "None")
+		}
+		for _, ref := range refs {
+			fmt.Fprintf(buffer, "- `%s`\n", ref)
+		}
+	}
+	writeList("Models without APIs", report.ModelsWithoutAPIs)
+	writeList("APIs with placeholder definitions", report.PlaceholderAPIs)
+	fmt.Fprintf(buffer, "\n## Entities with processing errors (%d)\n\n", len(report.ProcessingErrors))
+	if len(report.ProcessingErrors) == 0 {
+		fmt.Fprintln(buffer, "None")
+	}
+	for _, entity := range report.ProcessingErrors {
+		fmt.Fprintf(buffer, "- `%s`\n", entity.Ref)
+		for _, msg := range entity.Errors {
+			fmt.Fprintf(buffer, "  - %s\n", markdownEscape(msg))
+		}
+	}
+	return buffer.String()
+}
+
+// markdownEscape keeps values such as owners with a '|' from breaking the table
+func markdownEscape(value string) string {
+	return strings.ReplaceAll(value, "|", "\\|")
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gabemontero/backstage-ai-cli/pkg/cmd/cli/backstage"
+	"strings"
+	"testing"
+)
+
+const reportTableOutput = `FACET           VALUE         COUNT
+spec.type       model-server  2
+spec.type       openapi       2
+spec.owner      team-a        4
+spec.lifecycle  production    3
+spec.lifecycle  development   1
+
+Models without APIs: 1
+    Component:default/no-api
+
+APIs with placeholder definitions: 1
+    API:default/placeholder
+
+Entities with processing errors: 1
+    API:default/placeholder
+        InputError: missing definition
+`
+
+func reportCatalog() *fakeCatalog {
+	entity := func(kind, name string, spec map[string]interface{}) backstage.Entity {
+		return backstage.Entity{Kind: kind, Metadata: backstage.EntityMeta{Name: name, Namespace: "default"}, Spec: spec}
+	}
+	placeholder := entity(backstage.KindAPI, "placeholder", map[string]interface{}{"definition": backstage.API_DEFINITION_PLACEHOLDER})
+	placeholder.Status = &backstage.EntityStatus{Items: []backstage.EntityStatusItem{
+		{Level: "warning", Message: "slow"},
+		{Level: "error", Message: "Policy check failed", Error: &backstage.EntityStatusItemError{Name: "InputError", Message: "missing definition"}},
+	}}
+	withAPI := entity(backstage.KindComponent, "with-api", map[string]interface{}{})
+	withAPI.Relations = []backstage.EntityRelation{{Type: "providesApi", TargetRef: "api:default/with-api"}}
+	return &fakeCatalog{
+		query: map[string][]backstage.Entity{
+			strings.Join(backstage.DefaultEntityTypes().ModelFilters(), ";"): {
+				withAPI,
+				entity(backstage.KindComponent, "with-spec-api", map[string]interface{}{"providesApis": []interface{}{"with-spec-api"}}),
+				entity(backstage.KindComponent, "no-api", map[string]interface{}{}),
+				entity(backstage.KindAPI, "defined", map[string]interface{}{"definition": "openapi: 3.0.0"}),
+				placeholder,
+			},
+		},
+		facets: map[string][]backstage.FacetCount{
+			"spec.type":      {{Value: "openapi", Count: 2}, {Value: "model-server", Count: 2}},
+			"spec.owner":     {{Value: "team-a", Count: 4}},
+			"spec.lifecycle": {{Value: "development", Count: 1}, {Value: "production", Count: 3}},
+		},
+	}
+}
+
+func TestReportTable(t *testing.T) {
+	str, err := reportEntities(context.Background(), reportCatalog(), backstage.DefaultEntityTypes(), reportFormatTable)
+	assertContains(t, err, str)
+	assertEqual(t, reportTableOutput, str)
+}
+
+func TestReportJSON(t *testing.T) {
+	str, err := reportEntities(context.Background(), reportCatalog(), backstage.DefaultEntityTypes(), reportFormatJSON)
+	assertContains(t, err, str)
+	report := &inventoryReport{}
+	if err = json.Unmarshal([]byte(str), report); err != nil {
+		t.Fatal(err)
+	}
+	assertEqual(t, []string{"Component:default/no-api"}, report.ModelsWithoutAPIs)
+	assertEqual(t, []string{"API:default/placeholder"}, report.PlaceholderAPIs)
+	assertEqual(t, 1, len(report.ProcessingErrors))
+	assertEqual(t, 4, report.Counts["spec.owner"][0].Count)
+}
+
+func TestReportMarkdown(t *testing.T) {
+	str, err := reportEntities(context.Background(), reportCatalog(), backstage.DefaultEntityTypes(), reportFormatMarkdown)
+	assertContains(t, err, str,
+		"| spec.type | model-server | 2 |",
+		"## Models without APIs (1)\n\n- `Component:default/no-api`",
+		"## APIs with placeholder definitions (1)\n\n- `API:default/placeholder`",
+		"## Entities with processing errors (1)\n\n- `API:default/placeholder`\n  - InputError: missing definition")
+}
+
+func TestReportUnsupportedFormat(t *testing.T) {
+	_, err := reportEntities(context.Background(), reportCatalog(), backstage.DefaultEntityTypes(), "yaml")
+	if err == nil || !strings.Contains(err.Error(), `unsupported --output value "yaml"`) {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -1,16 +1,23 @@
 package cli
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
 	"github.com/gabemontero/backstage-ai-cli/pkg/cmd/cli/backstage"
 	"github.com/gabemontero/backstage-ai-cli/pkg/cmd/cli/kserve"
 	"github.com/gabemontero/backstage-ai-cli/pkg/cmd/cli/kubeflowmodelregistry"
 	"github.com/gabemontero/backstage-ai-cli/pkg/config"
 	"github.com/gabemontero/backstage-ai-cli/pkg/util"
 	"github.com/spf13/cobra"
+	"io"
 	"k8s.io/klog/v2"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -24,13 +31,45 @@ $ %s get [location|components|resources|apis] [args...]
 # Import from an accessible URL Backstage Catalog entities
 $ %s import-model <url>
 
-# Remove from the Backstage Catalog the Location entity for the provided Location ID.
+# Remove from the Backstage Catalog the Location entity for the provided Location ID, or individual entities
 $ %s delete-model <location id>
+$ %s delete entity <kind:namespace/name>
+
+# Validate Backstage Catalog Entity YAML, such as the output from new-model, before importing it
+$ %s validate <file|->
+
+# Have the Backstage Catalog process entities now, by entity reference or location ID
+$ %s refresh <kind:namespace/name|location id>...
+
+# Remove the entities Backstage has marked as orphans
+$ %s prune --orphans
+
+# Show an entity with its relations, the locations that imported it, and any processing errors
+$ %s describe <kind:namespace/name>
+
+# Summarize the AI Model inventory in the Backstage Catalog
+$ %s report [--output=table|json|markdown]
+
+# Count other spec.type values as AI related, here Resources for embedding models and vector databases, which can
+# also be set under 'types' in the config file, ~/.config/bac/config.yaml by default
+$ %s report --resource-types=api-model,embedding-model,vector-db
+
+# Back up the AI related entities in the Backstage Catalog, and import them into another Backstage instance
+$ %s export --output-dir=<dir>
+$ %s restore <dir> [--base-url=<url the directory is served from>]
 `
 
 	newModelExample = `
 # Access a supported backend for AI Model metadata and generate Backstage Catalog Entity YAML for that metadata
 $ %s new-model kserve [args]
+
+# Write a directory per model under ./catalog, each with a catalog-info.yaml and the TechDocs scaffolding (mkdocs.yml
+# and docs/index.md) for the Component, Resource, and API, ready to be pushed to Git and imported
+$ %s new-model kserve [args] --output-dir=./catalog
+
+# Show, as a unified diff of their YAML, what importing the generated entities would change in the Backstage Catalog,
+# exiting non-zero if any differ, as a check on a pull request updating the catalog
+$ %s new-model kserve [args] --diff --backstage-url=https://my-rhdh.com --backstage-token=my-token
 `
 
 	getExample = `
@@ -40,10 +79,19 @@ $ %s get <locations|components|resources|apis|entities> [args...]
 
 	deleteModelExample = `
 # Remove from the Backstage Catalog the Location entity for the provided Location ID, using the dynamically generated 
-# hash ID from when the location was imported.  There is not support in Backstage currently for specifying
-# the URL used to import the model as a query parameter.
+# hash ID from when the location was imported.  The entities read from the location are listed, and the delete has
+# to be confirmed.
 $ %s delete-model <location id>
 
+# Remove several Locations at once, without being asked for confirmation
+$ %s delete-model <location id> <location id> --yes
+
+# Remove the Location imported from the provided URL, for when the Location ID was not kept from the import
+$ %s delete-model --target=https://github.com/my-org/my-repo/blob/main/catalog-info.yaml
+
+# Remove individual entities by their kind:namespace/name reference
+$ %s delete entity component:default/my-model api:default/my-model
+
 # Set the URL for the Backstage instance, the authentication token, and Skip-TLS settings 
 $ %s delete-model <location id> --backstage-url=https://my-rhdh.com --backstage-token=my-token --backstage-skip-tls=true
 `
@@ -54,6 +102,73 @@ $ %s import-model <url>
 
 # Set the additional URL for the Backstage instance, the authentication token, and Skip-TLS settings 
 $ %s import-model <url> --backstage-url=https://my-rhdh.com --backstage-token=my-token --backstage-skip-tls=true
+
+# Have the Backstage instance read and process the entities at the URL, reporting any errors, without registering the location
+$ %s import-model <url> --dry-run=server
+`
+
+	validateExample = `
+# Validate the Backstage Catalog Entity YAML in a file against the Backstage catalog-model schemas and naming rules
+$ %s validate catalog-info.yaml
+
+# Validate the Backstage Catalog Entity YAML generated by new-model
+$ %s new-model kserve <owner> <lifecycle> | %s validate -
+
+# Have the Backstage instance's catalog processors validate the entities, as if imported from the provided location
+$ %s validate catalog-info.yaml --remote --location=url:https://github.com/my-org/my-repo/blob/main/catalog-info.yaml
+`
+
+	refreshExample = `
+# Have Backstage process an entity now, rather than on its next processing loop, after updating its YAML
+$ %s refresh component:default/my-model
+
+# Refresh every entity read from a location, using the location ID from when it was imported
+$ %s refresh my-big-long-id-for-location
+
+# Wait for Backstage to finish processing the entities, as seen by a change in their etag or processing status
+$ %s refresh component:default/my-model api:default/my-model --wait --wait-timeout=2m
+`
+
+	describeExample = `
+# Show an entity as a tree of its spec, its relations, such as the APIs a model server provides and the resources it
+# depends on, the locations that imported it, and any processing errors
+$ %s describe component:default/my-model
+`
+
+	reportExample = `
+# Summarize the AI related entities in the Backstage Catalog: counts by type, owner, lifecycle, tag and namespace,
+# the models without APIs, the APIs still with placeholder definitions, and the entities with processing errors
+$ %s report
+
+# Write the summary as markdown, ready to paste into a document, or as JSON for further processing
+$ %s report --output=markdown > inventory.md
+$ %s report -o json
+`
+
+	exportExample = `
+# Write every AI related entity, without the fields the catalog manages, to a catalog-info.yaml per location it
+# was imported from, under ./backup
+$ %s export --output-dir=./backup
+
+# Only export the model server Components and their APIs
+$ %s export --type=model-server,openapi --output-dir=./backup
+`
+
+	restoreExample = `
+# Import the files written by export after pushing them to Git, registering a URL location for each catalog-info.yaml
+$ %s restore ./backup --base-url=https://github.com/my-org/my-repo/blob/main/backup
+
+# Import the files as 'file' locations, for when the directory is on the host the Backstage instance runs on
+$ %s restore /var/backstage/backup
+`
+
+	pruneExample = `
+# Remove the AI related entities Backstage has marked as orphans, with the 'backstage.io/orphan=true' annotation,
+# because the location they were read from no longer includes them, after listing them and asking for confirmation
+$ %s prune --orphans
+
+# Remove the orphaned AI related entities without asking for confirmation
+$ %s prune --orphans --yes
 `
 
 	getEntitiesExample = `
@@ -79,7 +194,7 @@ $ %s get locations --backstage-url=https://my-rhdh.com --backstage-token=my-toke
 
 	getComponentsExample = `
 # Retrieve the Backstage Catalog for resources related to AI Models, where being AI related is determined by the 
-# 'type' being set to 'model-server'
+# 'type' being one of --component-types, by default 'model-server'
 $ %s get components [args...]
 
 # Set the URL for the Backstage, the authentication token, and Skip-TLS settings
@@ -97,7 +212,7 @@ $ %s get components gen-ai --use-params-as-tags=true --use-any-subset=true
 
 	getResourcesExample = `
 # Retrieve the Backstage Catalog for resources related to AI Models, where being AI related is determined by the 
-# 'type' being set to 'ai-model'
+# 'type' being one of --resource-types, by default 'api-model' or 'ai-model'
 $ %s get resources [args...]
 
 # Set the URL for the Backstage, the authentication token, and Skip-TLS settings
@@ -115,7 +230,7 @@ $ %s get resources gen-ai --use-params-as-tags=true --use-any-subset=true
 
 	getApisExample = `
 # Retrieve the Backstage Catalog for APIs related to AI Models, where being AI related is determined by the 
-# 'type' being set to 'model-service-api'
+# 'type' being one of --api-types, by default 'openapi'
 $ %s get apis [args...]
 
 # Set the URL for the Backstage, the authentication token, and Skip-TLS settings
@@ -132,6 +247,16 @@ $ %s get apis gen-ai --use-params-as-tags=true --use-any-subset=true
 `
 )
 
+const (
+	dryRunNone   = "none"
+	dryRunServer = "server"
+
+	defaultRefreshWaitTimeout = 2 * time.Minute
+)
+
+// refreshPollInterval is how often refresh --wait checks on the entities
+var refreshPollInterval = 2 * time.Second
+
 // NewCmd create a new root command, linking together all sub-commands organized by groups.
 func NewCmd() *cobra.Command {
 	cfg := &config.Config{}
@@ -170,6 +295,34 @@ func NewCmd() *cobra.Command {
 	bkstgAI.PersistentFlags().BoolVar(&(cfg.StoreSkipTLS), "model-metadata-skip-tls", cfg.StoreSkipTLS,
 		"Whether to skip use of TLS when accessing the external source for Model Metadata.")
 
+	bkstgAI.PersistentFlags().IntVar(&(cfg.Retries), "retries", util.DEFAULT_RETRIES,
+		"The number of times to retry a REST request that is throttled (429) or fails with a server error (5xx).")
+	bkstgAI.PersistentFlags().DurationVar(&(cfg.RetryWait), "retry-wait", util.DEFAULT_RETRY_WAIT,
+		"The initial wait before retrying a REST request, doubled with each retry unless the server sends Retry-After.")
+	bkstgAI.PersistentFlags().DurationVar(&(cfg.RetryMaxWait), "retry-max-wait", util.DEFAULT_RETRY_MAX_WAIT,
+		"The maximum wait between retries of a REST request.")
+	bkstgAI.PersistentFlags().DurationVar(&(cfg.Timeout), "timeout", util.DEFAULT_TIMEOUT,
+		"The time limit for each REST request to Backstage and the external source for Model Metadata, with 0 meaning no limit; --request-timeout overrides it for the Kubeflow Model Registry.")
+	bkstgAI.PersistentFlags().Float64Var(&(cfg.RateLimit), "rate-limit", cfg.RateLimit,
+		"The maximum number of REST requests per second across all clients, with 0 meaning no limit.")
+	bkstgAI.PersistentFlags().IntVar(&(cfg.RateBurst), "rate-burst", util.DEFAULT_RATE_BURST,
+		"The number of REST requests allowed in a burst above --rate-limit.")
+
+	defaultTypes := backstage.DefaultEntityTypes()
+	bkstgAI.PersistentFlags().StringVar(&(cfg.ConfigFile), "config", config.DefaultFilePath(),
+		fmt.Sprintf("The config file, whose settings apply unless set with flags; a file set with this flag or $%s has to exist.", config.CONFIG_FILE_ENV))
+	bkstgAI.PersistentFlags().StringSliceVar(&(cfg.ComponentTypes), "component-types", defaultTypes.Component,
+		"The spec.type values of AI related Components; the first is the type new-model generates.")
+	bkstgAI.PersistentFlags().StringSliceVar(&(cfg.ResourceTypes), "resource-types", defaultTypes.Resource,
+		"The spec.type values of AI related Resources; the first is the type new-model generates.")
+	bkstgAI.PersistentFlags().StringSliceVar(&(cfg.APITypes), "api-types", defaultTypes.API,
+		"The spec.type values of AI related APIs; the first is the type new-model generates.")
+	bkstgAI.PersistentFlags().StringSliceVar(&(cfg.SystemTypes), "system-types", defaultTypes.System,
+		"The spec.type values of AI related Systems; the first is the type new-model generates.")
+	bkstgAI.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		return applyConfigFile(cmd, cfg)
+	}
+
 	newModel := &cobra.Command{
 		Use:     "new-model",
 		Long:    "new-model accesses one of the supported backends and builds Backstage Catalog Entity YAML with available Model metadata",
@@ -180,8 +333,69 @@ func NewCmd() *cobra.Command {
 		},
 	}
 
-	newModel.AddCommand(kserve.NewCmd(cfg))
-	newModel.AddCommand(kubeflowmodelregistry.NewCmd(cfg))
+	newModel.PersistentFlags().StringVar(&(cfg.OutputDir), "output-dir", cfg.OutputDir,
+		"Write the entities for each model to <output-dir>/<model>/catalog-info.yaml, along with the TechDocs the entities reference, instead of to stdout")
+	newModel.PersistentFlags().BoolVar(&(cfg.Diff), "diff", cfg.Diff,
+		"Instead of printing the entities, show how they differ from the same entities in the Backstage Catalog, exiting non-zero if any do")
+
+	// with --diff, the entities the backend subcommand prints are captured and then compared with the catalog
+	var generated *bytes.Buffer
+	var stdout io.Writer
+	newModel.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		// cobra only runs the closest persistent pre-run, so the root's is called here
+		if err := bkstgAI.PersistentPreRunE(cmd, args); err != nil {
+			return err
+		}
+		if !cfg.Diff || cmd == newModel {
+			return nil
+		}
+		if len(cfg.OutputDir) > 0 {
+			err := fmt.Errorf("--diff compares the entities with the Backstage Catalog instead of writing them, so it cannot be used with --output-dir")
+			klog.Errorf("ERROR: %s", err.Error())
+			klog.Flush()
+			return err
+		}
+		generated = &bytes.Buffer{}
+		stdout = cmd.OutOrStdout()
+		cmd.SetOut(generated)
+		return nil
+	}
+	newModel.PersistentPostRunE = func(cmd *cobra.Command, args []string) error {
+		if generated == nil {
+			return nil
+		}
+		cmd.SetOut(stdout)
+		str, err := diffGenerated(commandContext(cmd), backstage.SetupBackstageRESTClient(cfg), generated)
+		printOutput(stdout, str, err)
+		// drift is the answer to --diff, not a misuse of the command, so it does not warrant the usage
+		cmd.SilenceUsage = true
+		return err
+	}
+
+	kserveCmd := kserve.NewCmd(cfg)
+	kserveCmd.Flags().BoolVar(&(cfg.ServingStatus), "status", cfg.ServingStatus,
+		"Add the readiness, model state, and traffic split of each InferenceService to its Component and TechDocs.")
+	newModel.AddCommand(kserveCmd)
+	kubeflowCmd := kubeflowmodelregistry.NewCmd(cfg)
+	kubeflowCmd.Flags().IntVar(&(cfg.Concurrency), "concurrency", kubeflowmodelregistry.DEFAULT_CONCURRENCY,
+		"The maximum number of registered models fetched from the Kubeflow Model Registry in parallel.")
+	kubeflowCmd.Flags().DurationVar(&(cfg.RequestTimeout), "request-timeout", cfg.RequestTimeout,
+		"The time limit for each request to the Kubeflow Model Registry, retries included, in place of --timeout; 0 means use --timeout.")
+	kubeflowCmd.Flags().IntVar(&(cfg.PageSize), "page-size", cfg.PageSize,
+		"The number of items to request per page from the Kubeflow Model Registry, with 0 meaning the registry default.")
+	kubeflowCmd.Flags().StringVar(&(cfg.OrderBy), "order-by", cfg.OrderBy,
+		"The field the Kubeflow Model Registry orders items by: CREATE_TIME, LAST_UPDATE_TIME, or Id.")
+	kubeflowCmd.Flags().StringVar(&(cfg.SortOrder), "sort-order", cfg.SortOrder,
+		"The direction the Kubeflow Model Registry sorts items in: ASC or DESC.")
+	kubeflowCmd.Flags().StringVar(&(cfg.ModelState), "state", cfg.ModelState,
+		"Only include registered models and model versions in this state: LIVE or ARCHIVED.")
+	kubeflowCmd.Flags().StringSliceVar(&(cfg.ModelNames), "name", cfg.ModelNames,
+		"Only include the registered models with these names; can be repeated or comma separated.")
+	kubeflowCmd.Flags().StringArrayVar(&(cfg.PropertyRules), "property-rule", cfg.PropertyRules,
+		"Map the custom properties matching a name or glob to a tag, label, annotation, link, or ignore them, as '<property>=<target>[:<key>]'; can be repeated.")
+	kubeflowCmd.Flags().StringVar(&(cfg.CatalogNamespace), "catalog-namespace", cfg.CatalogNamespace,
+		"The Backstage catalog namespace of the entities generated from the registry; the default namespace when not set.")
+	newModel.AddCommand(kubeflowCmd)
 
 	queryModel := &cobra.Command{
 		Use:     "get",
@@ -193,36 +407,212 @@ func NewCmd() *cobra.Command {
 		},
 	}
 	deleteModel := &cobra.Command{
-		Use:     "delete-model",
-		Long:    "delete-model removes the Backstage Catalog for Entities corresponding to the provided location ID",
+		Use:     "delete-model <location id>...",
+		Long:    "delete-model removes the Backstage Catalog for Entities corresponding to the provided location IDs, after listing the entities that will be removed and asking for confirmation",
 		Aliases: []string{"delete", "dm", "del", "d", "delete-models"},
 		Example: strings.ReplaceAll(deleteModelExample, "%s", util.ApplicationName),
-		Run: func(cmd *cobra.Command, args []string) {
-			if len(args) == 0 {
-				klog.Error("ERROR: delete-model requires a location ID")
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && len(cfg.DeleteTarget) == 0 {
+				return fmt.Errorf("delete-model requires one or more location IDs, or --target")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			str, err := deleteLocations(commandContext(cmd), backstage.SetupBackstageRESTClient(cfg), cfg, args, cmd.InOrStdin(), cmd.OutOrStdout())
+			processOutput(str, err)
+			return err
+		},
+	}
+	deleteModel.Flags().StringVar(&(cfg.DeleteTarget), "target", cfg.DeleteTarget,
+		"Remove the Location imported from this URL, instead of providing the Location ID")
+	deleteModel.Flags().BoolVarP(&(cfg.AssumeYes), "yes", "y", cfg.AssumeYes,
+		"Delete without asking for confirmation, as needed when stdin is not a terminal")
+	deleteModel.AddCommand(&cobra.Command{
+		Use:     "entity <kind:namespace/name>...",
+		Long:    "entity removes individual entities from the Backstage Catalog, regardless of the location they were read from",
+		Aliases: []string{"e", "entities"},
+		Example: strings.ReplaceAll(deleteModelExample, "%s", util.ApplicationName),
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			str, err := deleteEntities(commandContext(cmd), backstage.SetupBackstageRESTClient(cfg), args)
+			processOutput(str, err)
+			return err
+		},
+	})
+
+	describe := &cobra.Command{
+		Use:     "describe <kind:namespace/name>...",
+		Long:    "describe shows Backstage Catalog entities along with their relations, ancestry, and processing status",
+		Aliases: []string{"desc"},
+		Example: strings.ReplaceAll(describeExample, "%s", util.ApplicationName),
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client := backstage.SetupBackstageRESTClient(cfg)
+			for _, ref := range args {
+				str, err := describeEntity(commandContext(cmd), client, ref)
+				processOutput(str, err)
+				if err != nil {
+					return err
+				}
 			}
-			processOutput(backstage.SetupBackstageRESTClient(cfg).DeleteLocation(args[0]))
+			return nil
+		},
+	}
+
+	report := &cobra.Command{
+		Use:     "report",
+		Long:    "report summarizes the AI related entities in the Backstage Catalog, using the catalog's entity facets and queries",
+		Aliases: []string{"inventory"},
+		Example: strings.ReplaceAll(reportExample, "%s", util.ApplicationName),
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			str, err := reportEntities(commandContext(cmd), backstage.SetupBackstageRESTClient(cfg), backstage.NewEntityTypes(cfg), cfg.ReportFormat)
+			printOutput(cmd.OutOrStdout(), str, err)
+			return err
 		},
 	}
+	report.Flags().StringVarP(&(cfg.ReportFormat), "output", "o", reportFormatTable,
+		fmt.Sprintf("The format of the summary, one of %q, %q, or %q", reportFormatTable, reportFormatJSON, reportFormatMarkdown))
+
+	export := &cobra.Command{
+		Use:     "export",
+		Long:    "export writes the AI related entities in the Backstage Catalog to importable catalog-info.yaml files, grouped by the location they were imported from",
+		Aliases: []string{"backup"},
+		Example: strings.ReplaceAll(exportExample, "%s", util.ApplicationName),
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entityTypes := backstage.NewEntityTypes(cfg)
+			types := cfg.ExportTypes
+			if len(types) == 0 {
+				types = entityTypes.All()
+			}
+			str, err := exportEntities(commandContext(cmd), backstage.SetupBackstageRESTClient(cfg), entityTypes, types, cfg.OutputDir)
+			processOutput(str, err)
+			return err
+		},
+	}
+	export.Flags().StringSliceVar(&(cfg.ExportTypes), "type", cfg.ExportTypes,
+		"The spec.type values of the entities to export, defaulting to all of the AI related types")
+	export.Flags().StringVar(&(cfg.OutputDir), "output-dir", cfg.OutputDir,
+		"The directory to write a <location>/catalog-info.yaml to for each location the entities were imported from")
+
+	restore := &cobra.Command{
+		Use:     "restore <dir>",
+		Long:    "restore registers a Backstage Catalog location for each catalog-info.yaml under a directory written by export",
+		Example: strings.ReplaceAll(restoreExample, "%s", util.ApplicationName),
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			str, err := restoreEntities(commandContext(cmd), backstage.SetupBackstageRESTClient(cfg), args[0], cfg.RestoreBaseURL)
+			processOutput(str, err)
+			return err
+		},
+	}
+	restore.Flags().StringVar(&(cfg.RestoreBaseURL), "base-url", cfg.RestoreBaseURL,
+		"The URL the directory is available at, such as its location in a Git repository, instead of registering 'file' locations")
+
+	prune := &cobra.Command{
+		Use:     "prune",
+		Long:    "prune removes AI related entities from the Backstage Catalog that are no longer wanted, such as orphans, after listing the entities that will be removed and asking for confirmation",
+		Example: strings.ReplaceAll(pruneExample, "%s", util.ApplicationName),
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !cfg.PruneOrphans {
+				err := fmt.Errorf("prune requires --orphans, currently the only kind of entity it removes")
+				klog.Errorf("ERROR: %s", err.Error())
+				klog.Flush()
+				return err
+			}
+			str, err := pruneOrphans(commandContext(cmd), backstage.SetupBackstageRESTClient(cfg), cfg, backstage.NewEntityTypes(cfg), cmd.InOrStdin(), cmd.OutOrStdout())
+			processOutput(str, err)
+			return err
+		},
+	}
+	prune.Flags().BoolVar(&(cfg.PruneOrphans), "orphans", cfg.PruneOrphans,
+		"Remove the AI related entities annotated with 'backstage.io/orphan=true'")
+	prune.Flags().BoolVarP(&(cfg.AssumeYes), "yes", "y", cfg.AssumeYes,
+		"Delete without asking for confirmation, as needed when stdin is not a terminal")
+
 	importModel := &cobra.Command{
 		Use:     "import-model",
 		Long:    "import-model updates the Backstage Catalog with Entities contained in the provided location URL",
 		Aliases: []string{"post", "im", "p", "i", "import-models"},
 		Example: strings.ReplaceAll(importModelExample, "%s", util.ApplicationName),
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				err := fmt.Errorf("import-model requires a location URL")
+				klog.Errorf("ERROR: %s", err.Error())
+				klog.Flush()
+				return err
+			}
+			switch cfg.DryRun {
+			case dryRunNone:
+				str, err := formatImport(backstage.SetupBackstageRESTClient(cfg).ImportLocation(commandContext(cmd), args[0]))
+				processOutput(str, err)
+				return err
+			case dryRunServer:
+				str, err := dryRunImport(commandContext(cmd), backstage.SetupBackstageRESTClient(cfg), args[0])
+				processOutput(str, err)
+				return err
+			}
+			err := fmt.Errorf("unsupported --dry-run value %q, use either %q or %q", cfg.DryRun, dryRunNone, dryRunServer)
+			klog.Errorf("ERROR: %s", err.Error())
+			klog.Flush()
+			return err
+		},
+	}
+	importModel.Flags().StringVar(&(cfg.DryRun), "dry-run", dryRunNone,
+		fmt.Sprintf("Either %q, or %q to have Backstage process the entities at the URL without registering the location", dryRunNone, dryRunServer))
+
+	validate := &cobra.Command{
+		Use:     "validate",
+		Long:    "validate checks Backstage Catalog Entity YAML against the Backstage catalog-model schemas and naming rules",
+		Aliases: []string{"v", "lint"},
+		Example: strings.ReplaceAll(validateExample, "%s", util.ApplicationName),
+		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) == 0 {
-				klog.Error("ERROR: import-model requires a location URL")
+				err := fmt.Errorf("validate requires a file name, or '-' for stdin")
+				klog.Errorf("ERROR: %s", err.Error())
 				klog.Flush()
-				return
+				return err
 			}
-			processOutput(backstage.SetupBackstageRESTClient(cfg).ImportLocation(args[0]))
+			str, err := validateEntities(cmd, cfg, args[0])
+			processOutput(str, err)
+			return err
+		},
+	}
+	validate.Flags().BoolVar(&(cfg.RemoteValidation), "remote", cfg.RemoteValidation,
+		"Have the Backstage instance's catalog processors validate the entities instead of validating them locally")
+	validate.Flags().StringVar(&(cfg.ValidateLocation), "location", cfg.ValidateLocation,
+		"With --remote, the location reference the entities would be imported from, defaulting to 'file:<path of the file>'")
+
+	refresh := &cobra.Command{
+		Use:     "refresh <kind:namespace/name|location id>...",
+		Long:    "refresh has the Backstage Catalog process the provided entities, or the entities read from the provided locations, right away",
+		Aliases: []string{"r", "reprocess"},
+		Example: strings.ReplaceAll(refreshExample, "%s", util.ApplicationName),
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			str, err := refreshEntities(commandContext(cmd), backstage.SetupBackstageRESTClient(cfg), cfg, args)
+			processOutput(str, err)
+			return err
 		},
 	}
+	refresh.Flags().BoolVar(&(cfg.RefreshWait), "wait", cfg.RefreshWait,
+		"Wait until the etag or processing status of each refreshed entity changes")
+	refresh.Flags().DurationVar(&(cfg.RefreshWaitTimeout), "wait-timeout", defaultRefreshWaitTimeout,
+		"How long --wait waits for the refreshed entities to be processed")
 
 	bkstgAI.AddCommand(newModel)
 	bkstgAI.AddCommand(queryModel)
 	bkstgAI.AddCommand(deleteModel)
 	bkstgAI.AddCommand(importModel)
+	bkstgAI.AddCommand(validate)
+	bkstgAI.AddCommand(refresh)
+	bkstgAI.AddCommand(prune)
+	bkstgAI.AddCommand(describe)
+	bkstgAI.AddCommand(report)
+	bkstgAI.AddCommand(export)
+	bkstgAI.AddCommand(restore)
 
 	queryModel.AddCommand(&cobra.Command{
 		Use:     "entities",
@@ -231,7 +621,7 @@ func NewCmd() *cobra.Command {
 		Example: strings.ReplaceAll(getEntitiesExample, "%s", util.ApplicationName),
 		RunE: func(cmd *cobra.Command, args []string) error {
 
-			str, err := backstage.SetupBackstageRESTClient(cfg).ListEntities()
+			str, err := formatJSON(backstage.SetupBackstageRESTClient(cfg).ListEntities(commandContext(cmd)))
 			processOutput(str, err)
 			return err
 
@@ -244,7 +634,7 @@ func NewCmd() *cobra.Command {
 		Aliases: []string{"l", "location"},
 		Example: strings.ReplaceAll(getLocationsExample, "%s", util.ApplicationName),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			str, err := backstage.SetupBackstageRESTClient(cfg).GetLocation(args...)
+			str, err := formatJSON(backstage.SetupBackstageRESTClient(cfg).GetLocation(commandContext(cmd), args...))
 			processOutput(str, err)
 			return err
 		},
@@ -256,7 +646,7 @@ func NewCmd() *cobra.Command {
 		Aliases: []string{"c", "component"},
 		Example: strings.ReplaceAll(getComponentsExample, "%s", util.ApplicationName),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			str, err := backstage.SetupBackstageRESTClient(cfg).GetComponent(args...)
+			str, err := formatJSON(backstage.SetupBackstageRESTClient(cfg).GetComponent(commandContext(cmd), args...))
 			processOutput(str, err)
 			return err
 		},
@@ -268,7 +658,7 @@ func NewCmd() *cobra.Command {
 		Aliases: []string{"r", "resource"},
 		Example: strings.ReplaceAll(getResourcesExample, "%s", util.ApplicationName),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			str, err := backstage.SetupBackstageRESTClient(cfg).GetResource(args...)
+			str, err := formatJSON(backstage.SetupBackstageRESTClient(cfg).GetResource(commandContext(cmd), args...))
 			processOutput(str, err)
 			return err
 		},
@@ -280,7 +670,7 @@ func NewCmd() *cobra.Command {
 		Aliases: []string{"a", "api"},
 		Example: strings.ReplaceAll(getApisExample, "%s", util.ApplicationName),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			str, err := backstage.SetupBackstageRESTClient(cfg).GetAPI(args...)
+			str, err := formatJSON(backstage.SetupBackstageRESTClient(cfg).GetAPI(commandContext(cmd), args...))
 			processOutput(str, err)
 			return err
 		},
@@ -294,6 +684,310 @@ func NewCmd() *cobra.Command {
 	return bkstgAI
 }
 
+// commandContext is the context the command was executed with, which is canceled on an interrupt
+func commandContext(cmd *cobra.Command) context.Context {
+	if ctx := cmd.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+func dryRunImport(ctx context.Context, client backstage.CatalogClient, url string) (string, error) {
+	analysis, err := client.AnalyzeLocation(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	dryRun, err := client.DryRunImportLocation(ctx, url)
+	if err != nil {
+		return formatAnalysis(url, analysis), err
+	}
+	return formatAnalysis(url, analysis) + formatDryRun(dryRun), nil
+}
+
+// deleteLocations removes the locations for the provided IDs, plus the location imported from the --target URL.  The
+// entities that will disappear with each location are written out first, and unless --yes was set, the user has to
+// confirm the delete.
+func deleteLocations(ctx context.Context, client backstage.CatalogClient, cfg *config.Config, ids []string, in io.Reader, out io.Writer) (string, error) {
+	if len(cfg.DeleteTarget) > 0 {
+		location, err := client.GetLocationByTarget(ctx, cfg.DeleteTarget)
+		if err != nil {
+			return "", err
+		}
+		ids = append(ids, location.ID)
+	}
+	if len(ids) == 0 {
+		return "", fmt.Errorf("delete-model requires one or more location IDs, or --target")
+	}
+
+	for _, id := range ids {
+		locations, err := client.GetLocation(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		entities, err := client.GetLocationEntities(ctx, locations[0])
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(out, "Backstage location %s (%s:%s) will be deleted along with %d entities:\n", id, locations[0].Type, locations[0].Target, len(entities))
+		for _, entity := range entities {
+			fmt.Fprintf(out, "    %s\n", backstage.EntityRef(entity))
+		}
+	}
+
+	confirmed, err := confirmDelete(cfg, in, out)
+	if err != nil {
+		return "", err
+	}
+	if !confirmed {
+		return "nothing deleted\n", nil
+	}
+
+	buffer := &bytes.Buffer{}
+	for _, id := range ids {
+		str, err := formatDelete(id, client.DeleteLocation(ctx, id))
+		if err != nil {
+			return buffer.String(), err
+		}
+		fmt.Fprintln(buffer, str)
+	}
+	return buffer.String(), nil
+}
+
+// confirmDelete asks the user to confirm the delete previewed on out, unless --yes was set
+func confirmDelete(cfg *config.Config, in io.Reader, out io.Writer) (bool, error) {
+	if cfg.AssumeYes {
+		return true, nil
+	}
+	fmt.Fprint(out, "Delete? [y/N]: ")
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && len(answer) == 0 {
+		return false, fmt.Errorf("unable to read a confirmation, use --yes to delete without one: %s", err.Error())
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+func deleteEntities(ctx context.Context, client backstage.CatalogClient, refs []string) (string, error) {
+	buffer := &bytes.Buffer{}
+	for _, ref := range refs {
+		entity, err := client.DeleteEntity(ctx, ref)
+		if err != nil {
+			return buffer.String(), err
+		}
+		fmt.Fprintf(buffer, "%s (uid %s) deleted\n", backstage.EntityRef(*entity), entity.Metadata.UID)
+	}
+	return buffer.String(), nil
+}
+
+// pruneOrphans removes the orphaned entities of the AI types, leaving alone the Users, Groups, and other entities
+// Backstage orphans that this tool did not generate.  As with delete-model, the orphans are written out first, and
+// unless --yes was set, the user has to confirm the delete.
+func pruneOrphans(ctx context.Context, client backstage.CatalogClient, cfg *config.Config, types backstage.EntityTypes, in io.Reader, out io.Writer) (string, error) {
+	filters := []string{}
+	for _, filter := range append(types.ModelFilters(), types.Filters("system")...) {
+		filters = append(filters, fmt.Sprintf("%s,metadata.annotations.%s=true", filter, backstage.ORPHAN_ANNOTATION))
+	}
+	orphans, err := client.QueryEntities(ctx, filters...)
+	if err != nil {
+		return "", err
+	}
+	if len(orphans) == 0 {
+		return "no orphaned entities found\n", nil
+	}
+
+	fmt.Fprintf(out, "%d orphaned entities will be deleted:\n", len(orphans))
+	for _, orphan := range orphans {
+		fmt.Fprintf(out, "    %s\n", backstage.EntityRef(orphan))
+	}
+	confirmed, err := confirmDelete(cfg, in, out)
+	if err != nil {
+		return "", err
+	}
+	if !confirmed {
+		return "nothing deleted\n", nil
+	}
+
+	buffer := &bytes.Buffer{}
+	for _, orphan := range orphans {
+		if err = client.DeleteEntityByUID(ctx, orphan.Metadata.UID); err != nil {
+			return buffer.String(), err
+		}
+		fmt.Fprintf(buffer, "%s (uid %s) deleted\n", backstage.EntityRef(orphan), orphan.Metadata.UID)
+	}
+	return buffer.String(), nil
+}
+
+// refreshEntities requests a refresh of each entity, where arguments without a 'kind:' prefix are location IDs that
+// stand for every entity read from the location, and then with --wait waits for Backstage to process them
+func refreshEntities(ctx context.Context, client backstage.CatalogClient, cfg *config.Config, args []string) (string, error) {
+	refs := []string{}
+	for _, arg := range args {
+		if strings.Contains(arg, ":") {
+			refs = append(refs, arg)
+			continue
+		}
+		locations, err := client.GetLocation(ctx, arg)
+		if err != nil {
+			return "", err
+		}
+		entities, err := client.GetLocationEntities(ctx, locations[0])
+		if err != nil {
+			return "", err
+		}
+		if len(entities) == 0 {
+			klog.Warningf("no entities found for location %s (%s:%s)", arg, locations[0].Type, locations[0].Target)
+		}
+		for _, entity := range entities {
+			refs = append(refs, backstage.EntityRef(entity))
+		}
+	}
+
+	buffer := &bytes.Buffer{}
+	before := map[string]*backstage.Entity{}
+	for _, ref := range refs {
+		if cfg.RefreshWait {
+			entity, err := client.GetEntity(ctx, ref)
+			if err != nil {
+				return buffer.String(), err
+			}
+			before[ref] = entity
+		}
+		if err := client.RefreshEntity(ctx, ref); err != nil {
+			return buffer.String(), err
+		}
+		fmt.Fprintf(buffer, "%s: refresh requested\n", ref)
+	}
+	if !cfg.RefreshWait {
+		return buffer.String(), nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.RefreshWaitTimeout)
+	defer cancel()
+	for _, ref := range refs {
+		entity, err := backstage.WaitForRefresh(ctx, client, ref, before[ref], refreshPollInterval)
+		if err != nil {
+			return buffer.String(), fmt.Errorf("waiting for %s to be processed: %s", ref, err.Error())
+		}
+		fmt.Fprintf(buffer, "%s: processed (etag %s)\n", ref, entity.Metadata.Etag)
+	}
+	return buffer.String(), nil
+}
+
+func validateEntities(cmd *cobra.Command, cfg *config.Config, fileName string) (string, error) {
+	var reader io.Reader
+	if fileName == "-" {
+		reader = cmd.InOrStdin()
+	} else {
+		file, err := os.Open(fileName)
+		if err != nil {
+			return "", err
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	entities, err := backstage.ParseEntities(reader)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse entities from %s: %s", fileName, err.Error())
+	}
+
+	var client backstage.CatalogClient
+	location := cfg.ValidateLocation
+	if cfg.RemoteValidation {
+		client = backstage.SetupBackstageRESTClient(cfg)
+		if len(location) == 0 {
+			location = "file:" + fileName
+			if abs, err := filepath.Abs(fileName); err == nil && fileName != "-" {
+				location = "file:" + abs
+			}
+		}
+	}
+
+	buffer := &bytes.Buffer{}
+	invalid := 0
+	for _, entity := range entities {
+		var errs []error
+		if client != nil {
+			errs, err = client.ValidateEntityRemote(commandContext(cmd), entity, location)
+			if err != nil {
+				return buffer.String(), err
+			}
+		} else {
+			errs = backstage.ValidateEntity(entity)
+		}
+		if len(errs) == 0 {
+			fmt.Fprintf(buffer, "%s: valid\n", entityDisplayRef(entity))
+			continue
+		}
+		invalid++
+		fmt.Fprintf(buffer, "%s: invalid\n", entityDisplayRef(entity))
+		for _, e := range errs {
+			fmt.Fprintf(buffer, "    %s\n", e.Error())
+		}
+	}
+	if invalid > 0 {
+		return buffer.String(), fmt.Errorf("%d of %d entities from %s failed validation", invalid, len(entities), fileName)
+	}
+	return buffer.String(), nil
+}
+
+func entityDisplayRef(entity map[string]interface{}) string {
+	kind, _ := entity["kind"].(string)
+	namespace := backstage.DEFAULT_NS
+	name := ""
+	if metadata, ok := entity["metadata"].(map[string]interface{}); ok {
+		name, _ = metadata["name"].(string)
+		if ns, ok := metadata["namespace"].(string); ok && len(ns) > 0 {
+			namespace = ns
+		}
+	}
+	return fmt.Sprintf("%s:%s/%s", kind, namespace, name)
+}
+
+// applyConfigFile uses the settings from the config file for the flags that were not set
+func applyConfigFile(cmd *cobra.Command, cfg *config.Config) error {
+	required := cmd.Flags().Changed("config") || len(os.Getenv(config.CONFIG_FILE_ENV)) > 0
+	file, err := config.LoadFile(cfg.ConfigFile, required)
+	if err != nil {
+		klog.Errorf("ERROR: %s", err.Error())
+		klog.Flush()
+		return err
+	}
+	for flag, setting := range map[string]struct {
+		value    *[]string
+		fromFile []string
+	}{
+		"component-types": {&cfg.ComponentTypes, file.Types.Component},
+		"resource-types":  {&cfg.ResourceTypes, file.Types.Resource},
+		"api-types":       {&cfg.APITypes, file.Types.API},
+		"system-types":    {&cfg.SystemTypes, file.Types.System},
+		"property-rule":   {&cfg.PropertyRules, file.Kubeflow.Properties},
+	} {
+		if len(setting.fromFile) > 0 && !cmd.Flags().Changed(flag) {
+			*setting.value = setting.fromFile
+		}
+	}
+	if namespace, ok := file.Kubeflow.Namespaces[cfg.StoreURL]; ok && !cmd.Flags().Changed("catalog-namespace") {
+		cfg.CatalogNamespace = namespace
+	}
+	return nil
+}
+
+// printOutput writes what a command renders, like a report, to its stdout so it can be piped or redirected, leaving
+// klog for the errors
+func printOutput(out io.Writer, str string, err error) {
+	if len(str) > 0 {
+		fmt.Fprintln(out, strings.TrimSuffix(str, "\n"))
+	}
+	if err != nil {
+		klog.Errorf("%s", err.Error())
+		klog.Flush()
+	}
+}
+
 func processOutput(str string, err error) {
 	klog.Infoln(str)
 	klog.Flush()
@@ -1,8 +1,15 @@
 package cli
 
 import (
+	"bytes"
+	"fmt"
+	"github.com/gabemontero/backstage-ai-cli/pkg/config"
 	"github.com/gabemontero/backstage-ai-cli/test/stub"
 	"github.com/spf13/cobra"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -100,6 +107,51 @@ func TestNewCmd(t *testing.T) {
 			args:          []string{"get", "help", "entities"},
 			generatesHelp: true,
 		},
+		{
+			args:           []string{"validate"},
+			generatesError: true,
+			errorStr:       "validate requires a file name",
+		},
+		{
+			args:           []string{"validate", "no-such-file.yaml"},
+			generatesError: true,
+			errorStr:       "no such file or directory",
+		},
+		{
+			args:          []string{"validate", "--help"},
+			generatesHelp: true,
+		},
+		{
+			args:           []string{"import-model"},
+			generatesError: true,
+			errorStr:       "import-model requires a location URL",
+		},
+		{
+			args:           []string{"import-model", "foo"},
+			generatesError: true,
+			errorStr:       "unsupported protocol scheme",
+		},
+		{
+			args:           []string{"import-model", "https://my-repo/my.yaml", "--dry-run=client"},
+			generatesError: true,
+			errorStr:       "unsupported --dry-run value",
+		},
+		{
+			args:           []string{"import-model", "https://my-repo/my.yaml", "--dry-run=server"},
+			generatesError: true,
+			errorStr:       "unsupported protocol scheme",
+		},
+		{
+			args:           []string{"report", "--output=yaml"},
+			generatesError: true,
+			errorStr:       "unsupported --output value",
+		},
+		{
+			// flags stay set on the reused command, so this comes after the other new-model cases
+			args:           []string{"new-model", "kserve", "owner", "lifecycle", "--diff", "--output-dir=catalog"},
+			generatesError: true,
+			errorStr:       "cannot be used with --output-dir",
+		},
 	} {
 		subCmd, stdout, stderr, err := stub.ExecuteCommandC(cmd, tc.args...)
 		switch {
@@ -121,3 +173,94 @@ func testHelpOK(stdout string, cmd *cobra.Command) bool {
 	}
 	return false
 }
+
+func TestApplyConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	err := os.WriteFile(path, []byte("types:\n  component: [llm]\n  resource: [embedding-model, vector-db]\nkubeflow:\n  properties:\n  - team=label:example.com/team\n  namespaces:\n    https://my-kubeflow.com: team-a\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newCmd := func() (*cobra.Command, *config.Config) {
+		cfg := &config.Config{}
+		cmd := &cobra.Command{}
+		cmd.Flags().StringVar(&(cfg.ConfigFile), "config", "", "")
+		cmd.Flags().StringSliceVar(&(cfg.ComponentTypes), "component-types", []string{"model-server"}, "")
+		cmd.Flags().StringSliceVar(&(cfg.ResourceTypes), "resource-types", []string{"api-model"}, "")
+		cmd.Flags().StringSliceVar(&(cfg.APITypes), "api-types", []string{"openapi"}, "")
+		cmd.Flags().StringSliceVar(&(cfg.SystemTypes), "system-types", []string{"model-serving-environment"}, "")
+		cmd.Flags().StringArrayVar(&(cfg.PropertyRules), "property-rule", []string{}, "")
+		cmd.Flags().StringVar(&(cfg.CatalogNamespace), "catalog-namespace", "", "")
+		cfg.StoreURL = "https://my-kubeflow.com"
+		return cmd, cfg
+	}
+
+	// the flags set on the command line win over the file
+	cmd, cfg := newCmd()
+	assertContains(t, cmd.ParseFlags([]string{"--config", path, "--resource-types", "ai-model"}), "")
+	assertContains(t, applyConfigFile(cmd, cfg), "")
+	assertEqual(t, []string{"llm"}, cfg.ComponentTypes)
+	assertEqual(t, []string{"ai-model"}, cfg.ResourceTypes)
+	assertEqual(t, []string{"openapi"}, cfg.APITypes)
+	assertEqual(t, []string{"team=label:example.com/team"}, cfg.PropertyRules)
+	assertEqual(t, "team-a", cfg.CatalogNamespace)
+
+	// a missing default config file is fine, while a missing file that was asked for is not
+	cmd, cfg = newCmd()
+	cfg.ConfigFile = filepath.Join(dir, "missing.yaml")
+	assertContains(t, applyConfigFile(cmd, cfg), "")
+	assertEqual(t, []string{"model-server"}, cfg.ComponentTypes)
+	assertContains(t, cmd.ParseFlags([]string{"--config", filepath.Join(dir, "missing.yaml")}), "")
+	if err = applyConfigFile(cmd, cfg); err == nil {
+		t.Error("expected an error for the missing config file")
+	}
+
+	err = os.WriteFile(path, []byte("types:\n  models: [llm]\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd, cfg = newCmd()
+	assertContains(t, cmd.ParseFlags([]string{"--config", path}), "")
+	if err = applyConfigFile(cmd, cfg); err == nil || !strings.Contains(err.Error(), "invalid config file") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestPrintOutput(t *testing.T) {
+	out := &bytes.Buffer{}
+	printOutput(out, "Kind  Count\nAPI   2\n", nil)
+	assertEqual(t, "Kind  Count\nAPI   2\n", out.String())
+
+	// errors go to klog, leaving stdout with only what was rendered
+	out.Reset()
+	printOutput(out, "", fmt.Errorf("unable to query the catalog"))
+	assertEqual(t, "", out.String())
+}
+
+func TestNewModelDiffOutput(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	cmd := NewCmd()
+	newModel, _, err := cmd.Find([]string{"new-model"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	newModel.AddCommand(&cobra.Command{
+		Use: "fake",
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Fprint(cmd.OutOrStdout(), "apiVersion: backstage.io/v1alpha1\nkind: Component\nmetadata:\n  name: my-model\n")
+		},
+	})
+
+	// the diff goes to stdout, and the drift it shows is not a usage error
+	subCmd, stdout, _, err := stub.ExecuteCommandC(cmd, "new-model", "fake", "--diff", "--backstage-url="+ts.URL)
+	if err == nil || !strings.Contains(err.Error(), "1 of 1 generated entities differ") {
+		t.Errorf("unexpected error %v", err)
+	}
+	assertContains(t, nil, stdout, "+++ generated/Component:default/my-model", "+  name: my-model")
+	assertEqual(t, true, subCmd.SilenceUsage)
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// CONFIG_FILE_ENV names a config file to use instead of the default one
+	CONFIG_FILE_ENV = "BAC_CONFIG"
+
+	defaultConfigDir  = "bac"
+	defaultConfigFile = "config.yaml"
+)
+
+// File is the optional config file, whose settings are used unless the corresponding flags are set
+type File struct {
+	Types    FileTypes    `json:"types"`
+	Kubeflow FileKubeflow `json:"kubeflow"`
+}
+
+// FileTypes is the spec.type vocabulary of the AI related entities, for instance
+//
+//	types:
+//	  component: [model-server, llm]
+//	  resource: [api-model, embedding-model, vector-db]
+type FileTypes struct {
+	Component []string `json:"component,omitempty"`
+	Resource  []string `json:"resource,omitempty"`
+	API       []string `json:"api,omitempty"`
+	System    []string `json:"system,omitempty"`
+}
+
+// FileKubeflow is the Kubeflow Model Registry settings: the rules for mapping custom properties in the same
+// '<property>=<target>[:<key>]' form as --property-rule, and the catalog namespace of the entities from each registry
+// URL, for instance
+//
+//	kubeflow:
+//	  properties:
+//	  - accuracy=annotation
+//	  - dataset=link:Training dataset
+//	  - team=label:example.com/team
+//	  namespaces:
+//	    https://my-kubeflow.com: team-a
+type FileKubeflow struct {
+	Properties []string          `json:"properties,omitempty"`
+	Namespaces map[string]string `json:"namespaces,omitempty"`
+}
+
+// DefaultFilePath is $BAC_CONFIG, or bac/config.yaml under the user config directory, such as ~/.config
+func DefaultFilePath() string {
+	if path := os.Getenv(CONFIG_FILE_ENV); len(path) > 0 {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, defaultConfigDir, defaultConfigFile)
+}
+
+// LoadFile reads the config file at the path.  A missing file is only an error when required, as the default config
+// file does not have to exist.
+func LoadFile(path string, required bool) (*File, error) {
+	file := &File{}
+	if len(path) == 0 {
+		return file, nil
+	}
+	buf, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !required {
+		return file, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = yaml.UnmarshalStrict(buf, file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %s", path, err.Error())
+	}
+	return file, nil
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"github.com/go-resty/resty/v2"
 	servingv1beta1 "github.com/kserve/kserve/pkg/client/clientset/versioned/typed/serving/v1beta1"
+	"golang.org/x/time/rate"
+	"time"
 )
 
 type Config struct {
@@ -16,13 +18,42 @@ type Config struct {
 	StoreToken   string
 	StoreSkipTLS bool
 
+	// REST client related, shared by the Backstage and model metadata clients
+	Retries      int
+	RetryWait    time.Duration
+	RetryMaxWait time.Duration
+	Timeout      time.Duration
+	RateLimit    float64
+	RateBurst    int
+	Limiter      *rate.Limiter
+
 	// Backstage related
-	BackstageSkipTLS bool
-	BackstageToken   string
-	BackstageURL     string
+	BackstageRESTClient *resty.Client
+	BackstageSkipTLS    bool
+	BackstageToken      string
+	BackstageURL        string
+
+	// AI related entity type vocabulary, from the flags or the config file
+	ConfigFile     string
+	ComponentTypes []string
+	ResourceTypes  []string
+	APITypes       []string
+	SystemTypes    []string
 
 	// Kubeflow related
 	KubeflowRESTClient *resty.Client
+	Concurrency        int
+	RequestTimeout     time.Duration
+	PageSize           int
+	OrderBy            string
+	SortOrder          string
+	ModelState         string
+	ModelNames         []string
+	PropertyRules      []string
+	CatalogNamespace   string
+
+	// KServe related
+	ServingStatus bool
 
 	// new-model related
 	DeleteAll              bool
@@ -40,10 +71,35 @@ type Config struct {
 	ResourceTechDockRef    map[string]string
 	APITechDockRef         string
 	MultiEntryOutputPrefix string
+	OutputDir              string
+	Diff                   bool
 
 	// fetch-model related
 	ParamsAsTags   bool
 	AnySubsetWorks bool
+
+	// import-model related
+	DryRun string
+
+	// delete-model and prune related
+	DeleteTarget string
+	AssumeYes    bool
+	PruneOrphans bool
+
+	// refresh related
+	RefreshWait        bool
+	RefreshWaitTimeout time.Duration
+
+	// report related
+	ReportFormat string
+
+	// export and restore related
+	ExportTypes    []string
+	RestoreBaseURL string
+
+	// validate related
+	RemoteValidation bool
+	ValidateLocation string
 }
 
 type Link struct {
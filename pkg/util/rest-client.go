@@ -0,0 +1,103 @@
+package util
+
+import (
+	"errors"
+	"github.com/gabemontero/backstage-ai-cli/pkg/config"
+	"github.com/go-resty/resty/v2"
+	"golang.org/x/time/rate"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	DEFAULT_RETRIES        = 3
+	DEFAULT_RETRY_WAIT     = 500 * time.Millisecond
+	DEFAULT_RETRY_MAX_WAIT = 30 * time.Second
+	DEFAULT_TIMEOUT        = 60 * time.Second
+	DEFAULT_RATE_BURST     = 5
+
+	RETRY_AFTER_HEADER = "Retry-After"
+)
+
+// ConfigureRESTClient applies the retry, timeout, and rate limit settings to a REST client.  The rate limiter is kept
+// on the config so every client created from the same config draws from the same token bucket.
+func ConfigureRESTClient(client *resty.Client, cfg *config.Config) *resty.Client {
+	client.SetRetryCount(cfg.Retries).
+		SetRetryWaitTime(cfg.RetryWait).
+		SetRetryMaxWaitTime(cfg.RetryMaxWait).
+		SetRetryAfter(RetryAfter).
+		AddRetryCondition(ShouldRetry)
+	if cfg.Timeout > 0 {
+		client.SetTimeout(cfg.Timeout)
+	}
+	if limiter := RateLimiter(cfg); limiter != nil {
+		client.OnBeforeRequest(func(c *resty.Client, r *resty.Request) error {
+			return limiter.Wait(r.Context())
+		})
+	}
+	return client
+}
+
+// RateLimiter returns the token bucket shared by the REST clients of the config, or nil when there is no rate limit
+func RateLimiter(cfg *config.Config) *rate.Limiter {
+	if cfg.RateLimit <= 0 {
+		return nil
+	}
+	if cfg.Limiter == nil {
+		cfg.Limiter = rate.NewLimiter(rate.Limit(cfg.RateLimit), max(cfg.RateBurst, 1))
+	}
+	return cfg.Limiter
+}
+
+// ShouldRetry retries throttled requests, and for the methods that are safe to repeat, server errors along with
+// requests whose connection failed, but not requests that can never succeed like those with a malformed URL.  A POST
+// whose body the server may already have acted on, such as registering a location, is only retried when throttled.
+func ShouldRetry(resp *resty.Response, err error) bool {
+	if err != nil {
+		if resp == nil || resp.Request == nil || resp.Request.Context().Err() != nil || !idempotent(resp.Request.Method) {
+			return false
+		}
+		var opErr *net.OpError
+		return errors.As(err, &opErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+	}
+	if resp == nil {
+		return false
+	}
+	if resp.StatusCode() == http.StatusTooManyRequests {
+		return true
+	}
+	return resp.StatusCode() >= http.StatusInternalServerError && resp.Request != nil && idempotent(resp.Request.Method)
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// RetryAfter honors the Retry-After header, in either its seconds or HTTP date form, falling back to exponential
+// backoff when the header is not set.  A date already past waits the configured retry wait rather than the maximum.
+func RetryAfter(client *resty.Client, resp *resty.Response) (time.Duration, error) {
+	if resp == nil {
+		return 0, nil
+	}
+	value := resp.Header().Get(RETRY_AFTER_HEADER)
+	if len(value) == 0 {
+		return 0, nil
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait, nil
+		}
+		return client.RetryWaitTime, nil
+	}
+	return 0, nil
+}